@@ -0,0 +1,283 @@
+// Command bench-metrics compares storing metrics in a regular collection
+// with the indexes the server creates against a time-series collection. It
+// writes the same synthetic checks into two scratch collections of the
+// configured database, then times inserts and range queries on each.
+//
+//	go run ./cmd/bench-metrics [-monitors 20] [-checks 2000] [-workers 8] [-queries 200] [-hours 24]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"monitoring-tool/config"
+	"monitoring-tool/database"
+	"monitoring-tool/models"
+)
+
+// checkInterval spaces the synthetic checks of each monitor
+const checkInterval = 30 * time.Second
+
+// result holds the measurements of one collection
+type result struct {
+	name         string
+	insertTime   time.Duration
+	inserted     int
+	queryTimes   []time.Duration
+	returned     int
+	storageBytes int64
+	indexBytes   int64
+}
+
+func main() {
+	monitorCount := flag.Int("monitors", 20, "monitors to simulate")
+	checks := flag.Int("checks", 2000, "checks per monitor")
+	workers := flag.Int("workers", 8, "concurrent inserts, like concurrent monitor jobs")
+	queries := flag.Int("queries", 200, "range queries to time")
+	hours := flag.Int("hours", 24, "hours covered by each range query")
+	keep := flag.Bool("keep", false, "keep the scratch collections")
+	flag.Parse()
+
+	if *monitorCount < 1 || *checks < 1 || *workers < 1 || *queries < 1 || *hours < 1 {
+		log.Fatal("-monitors, -checks, -workers, -queries and -hours must be at least 1")
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	cfg := config.LoadConfig()
+	if err := cfg.Validate(); err != nil {
+		log.Fatal("Invalid configuration:", err)
+	}
+	if cfg.StorageDriver != "mongo" {
+		log.Fatal("bench-metrics requires STORAGE_DRIVER=mongo")
+	}
+
+	db, err := database.ConnectMongoDB(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to MongoDB:", err)
+	}
+	defer db.Disconnect(context.Background())
+
+	metrics := syntheticMetrics(*monitorCount, *checks, cfg.GetMetricsRetentionDuration())
+	span := time.Duration(*checks) * checkInterval
+
+	var results []result
+	for _, timeSeries := range []bool{false, true} {
+		r, err := run(db.Database, timeSeries, metrics, *workers, *queries, time.Duration(*hours)*time.Hour, span, *keep)
+		if err != nil {
+			log.Fatal("Benchmark failed:", err)
+		}
+		results = append(results, r)
+	}
+
+	fmt.Printf("\n%d monitors x %d checks, %d range queries of %dh\n\n", *monitorCount, *checks, *queries, *hours)
+	fmt.Printf("%-12s %12s %14s %14s %10s %12s %12s\n", "collection", "inserts/s", "query avg", "query p95", "docs/query", "data", "indexes")
+	for _, r := range results {
+		avg, p95 := summarize(r.queryTimes)
+		fmt.Printf("%-12s %12.0f %14s %14s %10d %12s %12s\n",
+			r.name,
+			float64(r.inserted)/r.insertTime.Seconds(),
+			avg.Round(time.Microsecond),
+			p95.Round(time.Microsecond),
+			r.returned/len(r.queryTimes),
+			formatBytes(r.storageBytes),
+			formatBytes(r.indexBytes),
+		)
+	}
+}
+
+// syntheticMetrics returns checks of every monitor spaced checkInterval
+// apart and ending now, in the order they would have been written
+func syntheticMetrics(monitors, checks int, retention time.Duration) []models.Metric {
+	random := rand.New(rand.NewSource(1))
+	ids := make([]primitive.ObjectID, monitors)
+	for i := range ids {
+		ids[i] = primitive.NewObjectID()
+	}
+
+	start := time.Now().Add(-time.Duration(checks) * checkInterval)
+	metrics := make([]models.Metric, 0, monitors*checks)
+	for c := 0; c < checks; c++ {
+		checkedAt := start.Add(time.Duration(c) * checkInterval)
+		for _, id := range ids {
+			status, code := "up", 200
+			if random.Intn(100) == 0 {
+				status, code = "down", 503
+			}
+			metrics = append(metrics, models.Metric{
+				MonitorID:    id,
+				URL:          "https://example.com/" + id.Hex(),
+				Status:       status,
+				StatusCode:   code,
+				ResponseTime: int64(50 + random.Intn(400)),
+				CheckedAt:    checkedAt,
+				ExpiresAt:    checkedAt.Add(retention),
+			})
+		}
+	}
+	return metrics
+}
+
+// run creates a scratch collection, inserts the metrics and times range queries on it
+func run(db *mongo.Database, timeSeries bool, metrics []models.Metric, workers, queries int, window, span time.Duration, keep bool) (result, error) {
+	ctx := context.Background()
+	r := result{name: "regular"}
+	if timeSeries {
+		r.name = "timeseries"
+	}
+	name := "bench_metrics_" + r.name
+
+	collection := db.Collection(name)
+	if err := collection.Drop(ctx); err != nil {
+		return r, err
+	}
+	if timeSeries {
+		// Long enough that no synthetic check expires during the run
+		if err := database.CreateTimeSeriesCollection(ctx, db, name, span+24*time.Hour); err != nil {
+			return r, err
+		}
+	} else if err := db.CreateCollection(ctx, name); err != nil {
+		return r, err
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, database.MetricsIndexes(timeSeries)); err != nil {
+		return r, err
+	}
+	if !keep {
+		defer collection.Drop(ctx)
+	}
+
+	log.Printf("Inserting %d metrics into %s", len(metrics), name)
+	var err error
+	if r.insertTime, err = insert(collection, metrics, workers); err != nil {
+		return r, err
+	}
+	r.inserted = len(metrics)
+
+	log.Printf("Running %d range queries on %s", queries, name)
+	random := rand.New(rand.NewSource(2))
+	end := metrics[len(metrics)-1].CheckedAt
+	for i := 0; i < queries; i++ {
+		monitorID := metrics[random.Intn(len(metrics))].MonitorID
+		from := end.Add(-span).Add(time.Duration(random.Int63n(int64(span))))
+		filter := bson.M{
+			"monitor_id": monitorID,
+			"checked_at": bson.M{"$gte": from, "$lt": from.Add(window)},
+		}
+
+		started := time.Now()
+		var found []models.Metric
+		cursor, err := collection.Find(ctx, filter, options.Find().SetSort(bson.M{"checked_at": -1}))
+		if err != nil {
+			return r, err
+		}
+		if err := cursor.All(ctx, &found); err != nil {
+			return r, err
+		}
+		r.queryTimes = append(r.queryTimes, time.Since(started))
+		r.returned += len(found)
+	}
+
+	r.storageBytes, r.indexBytes = storageStats(db, name)
+	return r, nil
+}
+
+// insert writes each metric with its own InsertOne, as the server does,
+// from several goroutines and returns the elapsed time
+func insert(collection *mongo.Collection, metrics []models.Metric, workers int) (time.Duration, error) {
+	jobs := make(chan models.Metric)
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+
+	started := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for metric := range jobs {
+				if _, err := collection.InsertOne(context.Background(), metric); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	for _, metric := range metrics {
+		select {
+		case jobs <- metric:
+		case err := <-errs:
+			close(jobs)
+			wg.Wait()
+			return 0, err
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	elapsed := time.Since(started)
+
+	select {
+	case err := <-errs:
+		return 0, err
+	default:
+	}
+	return elapsed, nil
+}
+
+// storageStats returns the on-disk size of a collection's data and indexes,
+// or zeros when the server does not report them
+func storageStats(db *mongo.Database, name string) (int64, int64) {
+	cursor, err := db.Collection(name).Aggregate(context.Background(), mongo.Pipeline{
+		{{Key: "$collStats", Value: bson.M{"storageStats": bson.M{}}}},
+	})
+	if err != nil {
+		log.Printf("Storage stats unavailable for %s: %v", name, err)
+		return 0, 0
+	}
+
+	var stats []struct {
+		StorageStats struct {
+			StorageSize    int64 `bson:"storageSize"`
+			TotalIndexSize int64 `bson:"totalIndexSize"`
+		} `bson:"storageStats"`
+	}
+	if err := cursor.All(context.Background(), &stats); err != nil || len(stats) == 0 {
+		log.Printf("Storage stats unavailable for %s: %v", name, err)
+		return 0, 0
+	}
+	return stats[0].StorageStats.StorageSize, stats[0].StorageStats.TotalIndexSize
+}
+
+// summarize returns the mean and 95th percentile of durations
+func summarize(durations []time.Duration) (time.Duration, time.Duration) {
+	if len(durations) == 0 {
+		return 0, 0
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	return total / time.Duration(len(sorted)), sorted[(len(sorted)*95+99)/100-1]
+}
+
+// formatBytes renders a size in MB
+func formatBytes(n int64) string {
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
@@ -0,0 +1,50 @@
+// Command migrate-metrics converts the MongoDB metrics collection into a
+// time-series collection. Stop the server before running it.
+//
+//	go run ./cmd/migrate-metrics [-batch 1000] [-keep-legacy]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/joho/godotenv"
+
+	"monitoring-tool/config"
+	"monitoring-tool/database"
+)
+
+func main() {
+	batchSize := flag.Int("batch", 1000, "metrics inserted per batch")
+	keepLegacy := flag.Bool("keep-legacy", false, "keep the regular collection as "+database.LegacyMetricsCollection)
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	cfg := config.LoadConfig()
+	if err := cfg.Validate(); err != nil {
+		log.Fatal("Invalid configuration:", err)
+	}
+	if cfg.StorageDriver != "mongo" {
+		log.Fatal("migrate-metrics requires STORAGE_DRIVER=mongo")
+	}
+	if *batchSize < 1 {
+		log.Fatal("-batch must be at least 1")
+	}
+
+	db, err := database.ConnectMongoDB(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to MongoDB:", err)
+	}
+	defer db.Disconnect(context.Background())
+
+	copied, err := database.MigrateMetricsToTimeSeries(db.Database, cfg.GetMetricsRetentionDuration(), *batchSize, *keepLegacy)
+	if err != nil {
+		log.Fatal("Migration failed:", err)
+	}
+
+	log.Printf("✅ Migrated %d metrics to a time-series collection. Set METRICS_TIMESERIES=true and start the server.", copied)
+}
@@ -6,8 +6,8 @@ import (
 	"log"
 	"os"
 	"strconv"
-	"time"
 	"strings"
+	"time"
 )
 
 // Config holds all configuration for the application
@@ -18,33 +18,59 @@ type Config struct {
 	Environment string
 
 	// Database configuration
+	StorageDriver string // mongo, memory, bolt
+	StoragePath   string // database file of the bolt driver
 	MongodbURI    string
 	DatabaseName  string
-	
+
+	// MetricsTimeSeries stores metrics in a MongoDB time-series collection
+	MetricsTimeSeries bool
+
 	// WebSocket configuration
 	WSReadTimeout  time.Duration
 	WSWriteTimeout time.Duration
 	WSPingPeriod   time.Duration
-	
+
 	// Monitoring configuration
-	DefaultInterval       int    // seconds
-	DefaultTimeout        int    // seconds
-	MaxConcurrentChecks   int
-	MetricsRetentionDays  int
-	
+	DefaultInterval      int // seconds
+	DefaultTimeout       int // seconds
+	MaxConcurrentChecks  int
+	MetricsRetentionDays int
+
+	// Retention of metric rollups, in days
+	RollupMinuteRetentionDays int
+	RollupHourRetentionDays   int
+	RollupDayRetentionDays    int
+
+	// Flap detection
+	FlapWindow         int // checks considered
+	FlapStartThreshold int // percent of checks changing state to start flapping
+	FlapStopThreshold  int // percent of checks changing state to stop flapping
+
 	// CORS configuration
 	AllowedOrigins []string
 
-	 // Production settings
-    TrustedProxies []string
-    ReadTimeout    time.Duration
-    WriteTimeout   time.Duration
-    IdleTimeout    time.Duration
-    
-    // Security
-    EnableHTTPS    bool
-    CertFile       string
-    KeyFile        string
+	// DashboardURL is linked from chat notifications
+	DashboardURL string
+
+	// Production settings
+	TrustedProxies []string
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	IdleTimeout    time.Duration
+
+	// Security
+	EnableHTTPS bool
+	CertFile    string
+	KeyFile     string
+
+	// SMTP relay for email notifications
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	SMTPStartTLS bool
 }
 
 // LoadConfig loads configuration from environment variables with defaults
@@ -56,8 +82,11 @@ func LoadConfig() *Config {
 		Environment: getEnvOrDefault("GIN_MODE", "debug"),
 
 		// Database
-		MongodbURI:   getEnvOrDefault("MONGODB_URI", "mongodb://localhost:27017"),
-		DatabaseName: getEnvOrDefault("DATABASE_NAME", "realtime_monitor"),
+		StorageDriver:     getEnvOrDefault("STORAGE_DRIVER", "mongo"),
+		StoragePath:       getEnvOrDefault("STORAGE_PATH", "monitor.db"),
+		MongodbURI:        getEnvOrDefault("MONGODB_URI", "mongodb://localhost:27017"),
+		DatabaseName:      getEnvOrDefault("DATABASE_NAME", "realtime_monitor"),
+		MetricsTimeSeries: getEnvAsBool("METRICS_TIMESERIES", false),
 
 		// WebSocket
 		WSReadTimeout:  time.Duration(getEnvAsInt("WS_READ_TIMEOUT", 60)) * time.Second,
@@ -70,29 +99,49 @@ func LoadConfig() *Config {
 		MaxConcurrentChecks:  getEnvAsInt("MAX_CONCURRENT_CHECKS", 100),
 		MetricsRetentionDays: getEnvAsInt("METRICS_RETENTION_DAYS", 30),
 
-		 // Production settings
-        TrustedProxies: getEnvAsStringSlice("TRUSTED_PROXIES", []string{}),
-        ReadTimeout:    time.Duration(getEnvAsInt("READ_TIMEOUT", 30)) * time.Second,
-        WriteTimeout:   time.Duration(getEnvAsInt("WRITE_TIMEOUT", 30)) * time.Second,
-        IdleTimeout:    time.Duration(getEnvAsInt("IDLE_TIMEOUT", 120)) * time.Second,
-        
-        // Security
-        EnableHTTPS:    getEnvAsBool("ENABLE_HTTPS", false),
-        CertFile:       getEnvOrDefault("CERT_FILE", ""),
-        KeyFile:        getEnvOrDefault("KEY_FILE", ""),
-        
-        // Update CORS for production
-        AllowedOrigins: getEnvAsStringSlice("ALLOWED_ORIGINS", []string{
-            getEnvOrDefault("FRONTEND_URL", "http://localhost:3000"),
-        }),
+		// Metric rollups
+		RollupMinuteRetentionDays: getEnvAsInt("ROLLUP_MINUTE_RETENTION_DAYS", 7),
+		RollupHourRetentionDays:   getEnvAsInt("ROLLUP_HOUR_RETENTION_DAYS", 90),
+		RollupDayRetentionDays:    getEnvAsInt("ROLLUP_DAY_RETENTION_DAYS", 730),
+
+		// Flap detection
+		FlapWindow:         getEnvAsInt("FLAP_WINDOW", 20),
+		FlapStartThreshold: getEnvAsInt("FLAP_START_THRESHOLD", 50),
+		FlapStopThreshold:  getEnvAsInt("FLAP_STOP_THRESHOLD", 25),
+
+		// Production settings
+		TrustedProxies: getEnvAsStringSlice("TRUSTED_PROXIES", []string{}),
+		ReadTimeout:    time.Duration(getEnvAsInt("READ_TIMEOUT", 30)) * time.Second,
+		WriteTimeout:   time.Duration(getEnvAsInt("WRITE_TIMEOUT", 30)) * time.Second,
+		IdleTimeout:    time.Duration(getEnvAsInt("IDLE_TIMEOUT", 120)) * time.Second,
+
+		// Security
+		EnableHTTPS: getEnvAsBool("ENABLE_HTTPS", false),
+		CertFile:    getEnvOrDefault("CERT_FILE", ""),
+		KeyFile:     getEnvOrDefault("KEY_FILE", ""),
+
+		// SMTP
+		SMTPHost:     getEnvOrDefault("SMTP_HOST", ""),
+		SMTPPort:     getEnvAsInt("SMTP_PORT", 587),
+		SMTPUsername: getEnvOrDefault("SMTP_USERNAME", ""),
+		SMTPPassword: getEnvOrDefault("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnvOrDefault("SMTP_FROM", ""),
+		SMTPStartTLS: getEnvAsBool("SMTP_STARTTLS", true),
+
+		// Update CORS for production
+		AllowedOrigins: getEnvAsStringSlice("ALLOWED_ORIGINS", []string{
+			getEnvOrDefault("FRONTEND_URL", "http://localhost:3000"),
+		}),
+
+		DashboardURL: getEnvOrDefault("DASHBOARD_URL", getEnvOrDefault("FRONTEND_URL", "http://localhost:3000")),
 	}
 }
 
 func getEnvAsStringSlice(key string, defaultValue []string) []string {
-    if valueStr := os.Getenv(key); valueStr != "" {
-        return strings.Split(valueStr, ",")
-    }
-    return defaultValue
+	if valueStr := os.Getenv(key); valueStr != "" {
+		return strings.Split(valueStr, ",")
+	}
+	return defaultValue
 }
 
 // GetServerAddress returns the full server address
@@ -117,45 +166,90 @@ func (c *Config) GetMetricsRetentionDuration() time.Duration {
 
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
-	if c.MongodbURI == "" {
-		return fmt.Errorf("MONGODB_URI is required")
+	switch c.StorageDriver {
+	case "mongo":
+		if c.MongodbURI == "" {
+			return fmt.Errorf("MONGODB_URI is required")
+		}
+
+		if c.DatabaseName == "" {
+			return fmt.Errorf("DATABASE_NAME is required")
+		}
+	case "memory":
+		log.Println("Warning: STORAGE_DRIVER is memory, data is lost when the server stops")
+	case "bolt":
+		if c.StoragePath == "" {
+			return fmt.Errorf("STORAGE_PATH is required when STORAGE_DRIVER is bolt")
+		}
+	default:
+		return fmt.Errorf("STORAGE_DRIVER must be mongo, memory or bolt")
+	}
+
+	if c.MetricsRetentionDays < 1 {
+		return fmt.Errorf("METRICS_RETENTION_DAYS must be at least 1")
 	}
-	
-	if c.DatabaseName == "" {
-		return fmt.Errorf("DATABASE_NAME is required")
+	if c.RollupMinuteRetentionDays < 1 || c.RollupHourRetentionDays < 1 || c.RollupDayRetentionDays < 1 {
+		return fmt.Errorf("ROLLUP_MINUTE_RETENTION_DAYS, ROLLUP_HOUR_RETENTION_DAYS and ROLLUP_DAY_RETENTION_DAYS must be at least 1")
 	}
-	
+
 	if c.DefaultInterval < 5 {
 		log.Printf("Warning: DEFAULT_INTERVAL is very low (%ds), this may cause high load", c.DefaultInterval)
 	}
-	
+
 	if c.DefaultTimeout >= c.DefaultInterval {
 		log.Printf("Warning: DEFAULT_TIMEOUT (%ds) should be less than DEFAULT_INTERVAL (%ds)", c.DefaultTimeout, c.DefaultInterval)
 	}
-	
-	  if c.IsProduction() {
-        if c.EnableHTTPS && (c.CertFile == "" || c.KeyFile == "") {
-            return fmt.Errorf("HTTPS enabled but certificate files not provided")
-        }
-        
-        if len(c.AllowedOrigins) == 0 {
-            log.Println("Warning: No CORS origins configured for production")
-        }
-    }
-    
-    return nil
+
+	if c.FlapWindow < 3 {
+		return fmt.Errorf("FLAP_WINDOW must be at least 3 checks")
+	}
+	if c.FlapStopThreshold >= c.FlapStartThreshold || c.FlapStartThreshold > 100 {
+		return fmt.Errorf("FLAP_STOP_THRESHOLD must be lower than FLAP_START_THRESHOLD (at most 100)")
+	}
+
+	if c.SMTPHost != "" && c.SMTPFrom == "" {
+		return fmt.Errorf("SMTP_FROM is required when SMTP_HOST is set")
+	}
+
+	if c.IsProduction() {
+		if c.EnableHTTPS && (c.CertFile == "" || c.KeyFile == "") {
+			return fmt.Errorf("HTTPS enabled but certificate files not provided")
+		}
+
+		if len(c.AllowedOrigins) == 0 {
+			log.Println("Warning: No CORS origins configured for production")
+		}
+	}
+
+	return nil
 }
 
 // LogConfig logs the current configuration (without sensitive data)
 func (c *Config) LogConfig() {
 	log.Printf("📋 Configuration loaded:")
 	log.Printf("   Server: %s (mode: %s)", c.GetServerAddress(), c.Environment)
-	log.Printf("   Database: %s", c.DatabaseName)
+	switch c.StorageDriver {
+	case "bolt":
+		log.Printf("   Storage: %s (file: %s)", c.StorageDriver, c.StoragePath)
+	case "mongo":
+		if c.MetricsTimeSeries {
+			log.Printf("   Storage: %s (database: %s, time-series metrics)", c.StorageDriver, c.DatabaseName)
+			break
+		}
+		log.Printf("   Storage: %s (database: %s)", c.StorageDriver, c.DatabaseName)
+	default:
+		log.Printf("   Storage: %s (database: %s)", c.StorageDriver, c.DatabaseName)
+	}
 	log.Printf("   Default monitoring interval: %ds", c.DefaultInterval)
 	log.Printf("   Default timeout: %ds", c.DefaultTimeout)
 	log.Printf("   Max concurrent checks: %d", c.MaxConcurrentChecks)
 	log.Printf("   Metrics retention: %d days", c.MetricsRetentionDays)
+	log.Printf("   Rollup retention: %d/%d/%d days (1m/1h/1d)", c.RollupMinuteRetentionDays, c.RollupHourRetentionDays, c.RollupDayRetentionDays)
+	log.Printf("   Flap detection: %d%%/%d%% of %d checks", c.FlapStartThreshold, c.FlapStopThreshold, c.FlapWindow)
 	log.Printf("   Allowed origins: %v", c.AllowedOrigins)
+	if c.SMTPHost != "" {
+		log.Printf("   SMTP relay: %s:%d (STARTTLS: %t)", c.SMTPHost, c.SMTPPort, c.SMTPStartTLS)
+	}
 }
 
 // Helper functions
@@ -197,18 +291,25 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 // GetTestConfig returns configuration for testing
 func GetTestConfig() *Config {
 	return &Config{
-		Port:                "8081",
-		Host:                "localhost",
-		Environment:         "test",
-		MongodbURI:         "mongodb://localhost:27017",
-		DatabaseName:       "realtime_monitor_test",
-		WSReadTimeout:      30 * time.Second,
-		WSWriteTimeout:     5 * time.Second,
-		WSPingPeriod:       25 * time.Second,
-		DefaultInterval:    10,
-		DefaultTimeout:     5,
-		MaxConcurrentChecks: 50,
-		MetricsRetentionDays: 7,
-		AllowedOrigins:     []string{"http://localhost:3000"},
-	}
-}
\ No newline at end of file
+		Port:                      "8081",
+		Host:                      "localhost",
+		Environment:               "test",
+		StorageDriver:             "memory",
+		MongodbURI:                "mongodb://localhost:27017",
+		DatabaseName:              "realtime_monitor_test",
+		WSReadTimeout:             30 * time.Second,
+		WSWriteTimeout:            5 * time.Second,
+		WSPingPeriod:              25 * time.Second,
+		DefaultInterval:           10,
+		DefaultTimeout:            5,
+		MaxConcurrentChecks:       50,
+		MetricsRetentionDays:      7,
+		RollupMinuteRetentionDays: 1,
+		RollupHourRetentionDays:   7,
+		RollupDayRetentionDays:    30,
+		FlapWindow:                20,
+		FlapStartThreshold:        50,
+		FlapStopThreshold:         25,
+		AllowedOrigins:            []string{"http://localhost:3000"},
+	}
+}
@@ -0,0 +1,146 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // DST cases need the zone database on every platform
+)
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"* * * *", "must have 5 fields"},
+		{"* * * * * *", "must have 5 fields"},
+		{"60 * * * *", "minute must be between 0 and 59"},
+		{"* 24 * * *", "hour must be between 0 and 23"},
+		{"* * 0 * *", "day of month must be between 1 and 31"},
+		{"* * 32 * *", "day of month must be between 1 and 31"},
+		{"* * * 13 *", "month must be between 1 and 12"},
+		{"* * * * 8", "day of week must be between 0 and 7"},
+		{"a * * * *", "minute must be between 0 and 59"},
+		{"-1 * * * *", "minute must be between 0 and 59"},
+		{"*/0 * * * *", "invalid step in minute field"},
+		{"*/x * * * *", "invalid step in minute field"},
+		{"30-10 * * * *", "invalid range in minute field"},
+		{"1,,2 * * * *", "minute must be between 0 and 59"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Parse(%q) err = %v, want %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	// Monday 1 January 2024
+	from := time.Date(2024, 1, 1, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want []string // successive matches, formatted as 2006-01-02 15:04
+	}{
+		{"every minute", "* * * * *", from, []string{"2024-01-01 10:08", "2024-01-01 10:09"}},
+		{"strictly after from", "8 10 * * *", from.Add(30 * time.Second).Truncate(time.Minute).Add(time.Minute), []string{"2024-01-02 10:08"}},
+		{"minute step", "*/15 * * * *", from, []string{"2024-01-01 10:15", "2024-01-01 10:30", "2024-01-01 10:45", "2024-01-01 11:00"}},
+		{"step from a value", "5/20 * * * *", from, []string{"2024-01-01 10:25", "2024-01-01 10:45", "2024-01-01 11:05"}},
+		{"range", "0 9-11 * * *", from, []string{"2024-01-01 11:00", "2024-01-02 09:00"}},
+		{"range with step", "0 0-12/6 * * *", from, []string{"2024-01-01 12:00", "2024-01-02 00:00", "2024-01-02 06:00"}},
+		{"list", "0,30 8,17 * * *", from, []string{"2024-01-01 17:00", "2024-01-01 17:30", "2024-01-02 08:00"}},
+		{"day of month", "0 0 15 * *", from, []string{"2024-01-15 00:00", "2024-02-15 00:00"}},
+		{"month", "0 0 1 3,6 *", from, []string{"2024-03-01 00:00", "2024-06-01 00:00", "2025-03-01 00:00"}},
+		{"leap day", "0 0 29 2 *", from, []string{"2024-02-29 00:00", "2028-02-29 00:00"}},
+		{"day of week", "0 2 * * 0", from, []string{"2024-01-07 02:00", "2024-01-14 02:00"}},
+		{"7 is Sunday", "0 2 * * 7", from, []string{"2024-01-07 02:00", "2024-01-14 02:00"}},
+		{"range ending on 7", "0 2 * * 6-7", from, []string{"2024-01-06 02:00", "2024-01-07 02:00", "2024-01-13 02:00"}},
+		{"weekdays", "0 9 * * 1-5", time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC), []string{"2024-01-08 09:00", "2024-01-09 09:00"}},
+		// Both restricted: a day matching either field matches
+		{"day of month or day of week", "0 0 10 * 3", from, []string{"2024-01-03 00:00", "2024-01-10 00:00", "2024-01-17 00:00", "2024-01-24 00:00", "2024-01-31 00:00", "2024-02-07 00:00", "2024-02-10 00:00"}},
+		// A field starting with * is unrestricted, so both must match: odd
+		// days of month that are Mondays
+		{"stepped day of month and day of week", "0 0 */2 * 1", from, []string{"2024-01-15 00:00", "2024-01-29 00:00", "2024-02-05 00:00"}},
+		{"day of month and stepped day of week", "0 0 1 * */3", from, []string{"2024-05-01 00:00", "2024-06-01 00:00", "2024-09-01 00:00"}},
+		{"never", "0 0 31 2 *", from, []string{"0001-01-01 00:00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.expr, err)
+			}
+
+			current := tt.from
+			for _, want := range tt.want {
+				current = schedule.Next(current)
+				if got := current.Format("2006-01-02 15:04"); got != want {
+					t.Fatalf("Next = %s, want %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNextAcrossDST(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load zone: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want []string // successive matches with their offset
+	}{
+		{
+			// 02:00-02:59 does not exist on 10 March 2024
+			name: "hourly through spring forward",
+			expr: "0 * * * *",
+			from: time.Date(2024, 3, 10, 0, 30, 0, 0, location),
+			want: []string{"2024-03-10 01:00 -0500", "2024-03-10 03:00 -0400", "2024-03-10 04:00 -0400"},
+		},
+		{
+			name: "daily at a skipped time",
+			expr: "30 2 * * *",
+			from: time.Date(2024, 3, 9, 3, 0, 0, 0, location),
+			want: []string{"2024-03-11 02:30 -0400"},
+		},
+		{
+			// 01:00-01:59 happens twice on 3 November 2024
+			name: "hourly through fall back",
+			expr: "0 * * * *",
+			from: time.Date(2024, 11, 3, 0, 30, 0, 0, location),
+			want: []string{"2024-11-03 01:00 -0400", "2024-11-03 01:00 -0500", "2024-11-03 02:00 -0500"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.expr, err)
+			}
+
+			current := tt.from
+			for _, want := range tt.want {
+				next := schedule.Next(current)
+				if got := next.Format("2006-01-02 15:04 -0700"); got != want {
+					t.Fatalf("Next(%s) = %s, want %s", current.Format("2006-01-02 15:04 -0700"), got, want)
+				}
+				if !next.After(current) {
+					t.Fatalf("Next(%s) = %s did not move forward", current, next)
+				}
+				current = next
+			}
+		})
+	}
+}
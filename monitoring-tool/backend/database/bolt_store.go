@@ -0,0 +1,1052 @@
+// database/bolt_store.go
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/models"
+)
+
+// Buckets of the embedded database. Documents are stored as BSON, so they
+// keep the same field names and encoding as in MongoDB.
+var (
+	monitorsBucket         = []byte(MonitorsCollection)
+	metricsBucket          = []byte(MetricsCollection)       // one nested bucket per monitor, keyed by check time
+	rollupsBucket          = []byte(MetricRollupsCollection) // nested buckets per monitor and resolution, keyed by bucket start
+	incidentsBucket        = []byte(IncidentsCollection)
+	openIncidentsBucket    = []byte("open_incidents")    // ID of each monitor's open incident, keyed by monitor ID
+	monitorIncidentsBucket = []byte("monitor_incidents") // one nested bucket per monitor holding its incident IDs
+	channelsBucket         = []byte(NotificationChannelsCollection)
+	deliveriesBucket       = []byte(NotificationDeliveriesCollection) // keyed by creation time
+	policiesBucket         = []byte(EscalationPoliciesCollection)
+	windowsBucket          = []byte(MaintenanceWindowsCollection)
+
+	metaBucket       = []byte("meta")
+	schemaVersionKey = []byte("schema_version")
+)
+
+// boltPruneInterval is how often expired metrics, rollups and deliveries are removed
+const boltPruneInterval = time.Hour
+
+// boltMigrations bring a database file up to the current schema. They run
+// in order inside a single transaction and the number applied is stored as
+// the schema version, so each runs exactly once per file. Append new
+// migrations; never change or reorder existing ones.
+var boltMigrations = []func(tx *bbolt.Tx) error{
+	// 1: initial layout
+	func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{monitorsBucket, metricsBucket, incidentsBucket, channelsBucket, deliveriesBucket, policiesBucket, windowsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	// 2: metric rollups
+	func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(rollupsBucket)
+		return err
+	},
+	// 3: incident indexes by monitor
+	func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{openIncidentsBucket, monitorIncidentsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		incidents, err := listDocs[models.Incident](tx.Bucket(incidentsBucket))
+		if err != nil {
+			return err
+		}
+		// Oldest first, so the newest open incident of a monitor is indexed
+		// as the open one, as FindOpen returned before
+		sortNewestFirst(incidents)
+		for i := len(incidents) - 1; i >= 0; i-- {
+			if err := indexIncident(tx, &incidents[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// BoltStore keeps everything in a single local file using bbolt
+type BoltStore struct {
+	db        *bbolt.DB
+	retention time.Duration // how long metrics are kept unless the monitor overrides it
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// OpenBoltStore opens (or creates) the database file at path, applies any
+// pending schema migrations and starts removing metrics older than retention,
+// or the monitor's own retention when it sets one
+func OpenBoltStore(path string, retention time.Duration) (*BoltStore, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+
+	version, err := migrateBolt(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate %s: %v", path, err)
+	}
+
+	s := &BoltStore{
+		db:        db,
+		retention: retention,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go s.enforceRetention()
+
+	log.Printf("✅ Opened embedded database: %s (schema version %d)", path, version)
+	return s, nil
+}
+
+// migrateBolt applies the migrations the file has not seen yet and returns
+// the resulting schema version
+func migrateBolt(db *bbolt.DB) (int, error) {
+	var version int
+	err := db.Update(func(tx *bbolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		if v := meta.Get(schemaVersionKey); v != nil {
+			version = int(binary.BigEndian.Uint64(v))
+		}
+		if version > len(boltMigrations) {
+			return fmt.Errorf("schema version %d is newer than this build supports (%d)", version, len(boltMigrations))
+		}
+
+		for ; version < len(boltMigrations); version++ {
+			log.Printf("🔧 Applying embedded database migration %d", version+1)
+			if err := boltMigrations[version](tx); err != nil {
+				return fmt.Errorf("migration %d: %v", version+1, err)
+			}
+		}
+
+		return meta.Put(schemaVersionKey, binary.BigEndian.AppendUint64(nil, uint64(version)))
+	})
+	return version, err
+}
+
+func (s *BoltStore) Monitors() MonitorStore {
+	return boltMonitorStore{s.db}
+}
+
+func (s *BoltStore) Metrics() MetricStore {
+	return boltMetricStore{s.db}
+}
+
+func (s *BoltStore) Rollups() RollupStore {
+	return boltRollupStore{s.db}
+}
+
+func (s *BoltStore) Incidents() IncidentStore {
+	return boltIncidentStore{s.db}
+}
+
+func (s *BoltStore) Notifications() NotificationStore {
+	return boltNotificationStore{s.db}
+}
+
+func (s *BoltStore) EscalationPolicies() EscalationPolicyStore {
+	return boltEscalationPolicyStore{s.db}
+}
+
+func (s *BoltStore) MaintenanceWindows() MaintenanceWindowStore {
+	return boltMaintenanceWindowStore{s.db}
+}
+
+// Health checks that the database file is open
+func (s *BoltStore) Health() error {
+	return s.db.View(func(tx *bbolt.Tx) error { return nil })
+}
+
+// Close stops retention enforcement and closes the database file
+func (s *BoltStore) Close(ctx context.Context) error {
+	close(s.stop)
+	<-s.done
+	return s.db.Close()
+}
+
+// enforceRetention removes expired data until the store is closed
+func (s *BoltStore) enforceRetention() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(boltPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.prune(time.Now()); err != nil {
+			log.Printf("Error removing expired data: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// prune deletes metrics, rollups and deliveries that are past their retention at now
+func (s *BoltStore) prune(now time.Time) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		monitors := tx.Bucket(monitorsBucket)
+		metrics := tx.Bucket(metricsBucket)
+		err := metrics.ForEachBucket(func(monitorID []byte) error {
+			retention := s.retention
+			if monitor, err := getDoc[models.Monitor](monitors, monitorID); err == nil {
+				retention = monitor.MetricsRetention(retention)
+			}
+			return deleteBefore(metrics.Bucket(monitorID), now.Add(-retention))
+		})
+		if err != nil {
+			return err
+		}
+
+		rollups := tx.Bucket(rollupsBucket)
+		err = rollups.ForEachBucket(func(monitorID []byte) error {
+			monitor := rollups.Bucket(monitorID)
+			return monitor.ForEachBucket(func(resolution []byte) error {
+				return deleteExpiredRollups(monitor.Bucket(resolution), now)
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		return deleteBefore(tx.Bucket(deliveriesBucket), now.Add(-deliveryRetention))
+	})
+}
+
+// deleteBefore removes the entries of a time-keyed bucket older than cutoff
+func deleteBefore(bucket *bbolt.Bucket, cutoff time.Time) error {
+	var expired [][]byte
+	c := bucket.Cursor()
+	for k, _ := c.First(); k != nil && timeOfKey(k).Before(cutoff); k, _ = c.Next() {
+		expired = append(expired, append([]byte(nil), k...))
+	}
+
+	for _, k := range expired {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteExpiredRollups removes the rollups of a resolution bucket that
+// expired before now. Every rollup of a resolution is kept for the same
+// time, so they expire in key order.
+func deleteExpiredRollups(bucket *bbolt.Bucket, now time.Time) error {
+	var expired [][]byte
+	c := bucket.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		var rollup models.MetricRollup
+		if err := bson.Unmarshal(v, &rollup); err != nil {
+			return err
+		}
+		if rollup.ExpiresAt.After(now) {
+			break
+		}
+		expired = append(expired, append([]byte(nil), k...))
+	}
+
+	for _, k := range expired {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// timeKey orders entries by time, with the ID keeping keys unique
+func timeKey(t time.Time, id primitive.ObjectID) []byte {
+	return append(binary.BigEndian.AppendUint64(nil, uint64(t.UnixNano())), id[:]...)
+}
+
+// timeOfKey returns the time a timeKey was built from
+func timeOfKey(key []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key[:8])))
+}
+
+// getDoc decodes the document stored under key
+func getDoc[T any](bucket *bbolt.Bucket, key []byte) (*T, error) {
+	data := bucket.Get(key)
+	if data == nil {
+		return nil, ErrNotFound
+	}
+
+	var doc T
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// putDoc encodes and stores a document under key
+func putDoc(bucket *bbolt.Bucket, key []byte, doc interface{}) error {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
+
+// listDocs decodes every document of a bucket, in key order
+func listDocs[T any](bucket *bbolt.Bucket) ([]T, error) {
+	docs := []T{}
+	err := bucket.ForEach(func(k, v []byte) error {
+		var doc T
+		if err := bson.Unmarshal(v, &doc); err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+		return nil
+	})
+	return docs, err
+}
+
+// updateDoc applies fn to the document stored under id and saves it
+func updateDoc[T any](db *bbolt.DB, name []byte, id primitive.ObjectID, fn func(*T) error) (*T, error) {
+	var doc *T
+	err := db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(name)
+
+		var err error
+		if doc, err = getDoc[T](bucket, id[:]); err != nil {
+			return err
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+		return putDoc(bucket, id[:], doc)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// insertDoc stores a new document keyed by its ID
+func insertDoc(db *bbolt.DB, name []byte, id primitive.ObjectID, doc interface{}) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		return putDoc(tx.Bucket(name), id[:], doc)
+	})
+}
+
+// deleteDoc removes the document stored under id
+func deleteDoc(db *bbolt.DB, name []byte, id primitive.ObjectID) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(name)
+		if bucket.Get(id[:]) == nil {
+			return ErrNotFound
+		}
+		return bucket.Delete(id[:])
+	})
+}
+
+// viewDoc decodes a single document by ID
+func viewDoc[T any](db *bbolt.DB, name []byte, id primitive.ObjectID) (*T, error) {
+	var doc *T
+	err := db.View(func(tx *bbolt.Tx) error {
+		var err error
+		doc, err = getDoc[T](tx.Bucket(name), id[:])
+		return err
+	})
+	return doc, err
+}
+
+// viewDocs decodes every document of a bucket
+func viewDocs[T any](db *bbolt.DB, name []byte) ([]T, error) {
+	var docs []T
+	err := db.View(func(tx *bbolt.Tx) error {
+		var err error
+		docs, err = listDocs[T](tx.Bucket(name))
+		return err
+	})
+	return docs, err
+}
+
+type boltMonitorStore struct {
+	db *bbolt.DB
+}
+
+func (s boltMonitorStore) Create(monitor *models.Monitor) error {
+	monitor.ID = primitive.NewObjectID()
+	return insertDoc(s.db, monitorsBucket, monitor.ID, monitor)
+}
+
+func (s boltMonitorStore) List() ([]models.Monitor, error) {
+	return viewDocs[models.Monitor](s.db, monitorsBucket)
+}
+
+func (s boltMonitorStore) Get(id primitive.ObjectID) (*models.Monitor, error) {
+	return viewDoc[models.Monitor](s.db, monitorsBucket, id)
+}
+
+func (s boltMonitorStore) TargetExists(target *models.Monitor, excludeID *primitive.ObjectID) (bool, error) {
+	monitors, err := s.List()
+	if err != nil {
+		return false, err
+	}
+
+	for i := range monitors {
+		monitor := &monitors[i]
+		if sameTarget(monitor, target) && (excludeID == nil || monitor.ID != *excludeID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s boltMonitorStore) UpdateSettings(monitor *models.Monitor) error {
+	_, err := updateDoc(s.db, monitorsBucket, monitor.ID, func(stored *models.Monitor) error {
+		applySettings(stored, *monitor)
+		return nil
+	})
+	return err
+}
+
+func (s boltMonitorStore) Pause(id primitive.ObjectID, pausedBy, reason string, at time.Time) (*models.Monitor, error) {
+	return updateDoc(s.db, monitorsBucket, id, func(monitor *models.Monitor) error {
+		pause(monitor, pausedBy, reason, at)
+		return nil
+	})
+}
+
+func (s boltMonitorStore) Resume(id primitive.ObjectID, at time.Time) (*models.Monitor, error) {
+	return updateDoc(s.db, monitorsBucket, id, func(monitor *models.Monitor) error {
+		resume(monitor, at)
+		return nil
+	})
+}
+
+func (s boltMonitorStore) Delete(id primitive.ObjectID) error {
+	return deleteDoc(s.db, monitorsBucket, id)
+}
+
+func (s boltMonitorStore) UpdateStatus(id primitive.ObjectID, status string, statusCode int, responseTime int64, checkedAt time.Time) (string, error) {
+	var previous string
+	_, err := updateDoc(s.db, monitorsBucket, id, func(monitor *models.Monitor) error {
+		previous = monitor.CurrentStatus
+		monitor.CurrentStatus = status
+		monitor.CurrentResponse = int(responseTime)
+		monitor.LastChecked = &checkedAt
+		monitor.UpdatedAt = time.Now()
+		return nil
+	})
+	return previous, err
+}
+
+func (s boltMonitorStore) UpdateCertificate(id primitive.ObjectID, info *models.TLSInfo) error {
+	return ignoreNotFound(updateDoc(s.db, monitorsBucket, id, func(monitor *models.Monitor) error {
+		monitor.Certificate = info
+		return nil
+	}))
+}
+
+func (s boltMonitorStore) SetFlapping(id primitive.ObjectID, flapping bool) error {
+	return ignoreNotFound(updateDoc(s.db, monitorsBucket, id, func(monitor *models.Monitor) error {
+		monitor.IsFlapping = flapping
+		return nil
+	}))
+}
+
+func (s boltMonitorStore) ClearFlapping() error {
+	return updateAll(s.db, monitorsBucket, func(monitor *models.Monitor) bool {
+		if !monitor.IsFlapping {
+			return false
+		}
+		monitor.IsFlapping = false
+		return true
+	})
+}
+
+func (s boltMonitorStore) DetachEscalationPolicy(policyID primitive.ObjectID) error {
+	return updateAll(s.db, monitorsBucket, func(monitor *models.Monitor) bool {
+		if monitor.EscalationPolicyID == nil || *monitor.EscalationPolicyID != policyID {
+			return false
+		}
+		monitor.EscalationPolicyID = nil
+		return true
+	})
+}
+
+// ignoreNotFound drops the document of an update that, like an UpdateOne
+// matching nothing, is not an error
+func ignoreNotFound[T any](_ T, err error) error {
+	if err == ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// updateAll applies fn to every document of a bucket, saving those it reports as changed
+func updateAll[T any](db *bbolt.DB, name []byte, fn func(*T) bool) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(name)
+
+		changed := map[string]*T{}
+		err := bucket.ForEach(func(k, v []byte) error {
+			var doc T
+			if err := bson.Unmarshal(v, &doc); err != nil {
+				return err
+			}
+			if fn(&doc) {
+				changed[string(k)] = &doc
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for k, doc := range changed {
+			if err := putDoc(bucket, []byte(k), doc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type boltMetricStore struct {
+	db *bbolt.DB
+}
+
+func (s boltMetricStore) Insert(metric *models.Metric) error {
+	metric.ID = primitive.NewObjectID()
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.Bucket(metricsBucket).CreateBucketIfNotExists(metric.MonitorID[:])
+		if err != nil {
+			return err
+		}
+		return putDoc(bucket, timeKey(metric.CheckedAt, metric.ID), metric)
+	})
+}
+
+func (s boltMetricStore) Since(monitorID primitive.ObjectID, since time.Time, limit int) ([]models.Metric, error) {
+	var metrics []models.Metric
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(metricsBucket).Bucket(monitorID[:])
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		for k, v := c.Last(); k != nil && !timeOfKey(k).Before(since); k, v = c.Prev() {
+			var metric models.Metric
+			if err := bson.Unmarshal(v, &metric); err != nil {
+				return err
+			}
+			metrics = append(metrics, metric)
+			if limit > 0 && len(metrics) == limit {
+				break
+			}
+		}
+		return nil
+	})
+	return metrics, err
+}
+
+func (s boltMetricStore) Between(monitorID primitive.ObjectID, from, to time.Time) ([]models.Metric, error) {
+	var metrics []models.Metric
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(metricsBucket).Bucket(monitorID[:])
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		for k, v := c.Seek(timeKey(from, primitive.NilObjectID)); k != nil && timeOfKey(k).Before(to); k, v = c.Next() {
+			var metric models.Metric
+			if err := bson.Unmarshal(v, &metric); err != nil {
+				return err
+			}
+			metrics = append(metrics, metric)
+		}
+		return nil
+	})
+	return metrics, err
+}
+
+func (s boltMetricStore) CountSince(monitorID primitive.ObjectID, since time.Time) (MetricCounts, error) {
+	var counts MetricCounts
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(metricsBucket).Bucket(monitorID[:])
+		if bucket == nil {
+			return nil
+		}
+
+		// Only the fields counted are decoded
+		var metric struct {
+			Status        string `bson:"status"`
+			InMaintenance bool   `bson:"in_maintenance"`
+		}
+		c := bucket.Cursor()
+		for k, v := c.Seek(timeKey(since, primitive.NilObjectID)); k != nil; k, v = c.Next() {
+			metric.InMaintenance = false
+			if err := bson.Unmarshal(v, &metric); err != nil {
+				return err
+			}
+			counts.add(metric.Status, metric.InMaintenance)
+		}
+		return nil
+	})
+	return counts, err
+}
+
+// SetRetention does nothing: the retention is looked up whenever metrics are pruned
+func (s boltMetricStore) PerMonitorRetention() bool {
+	return true
+}
+
+func (s boltMetricStore) SetRetention(monitorID primitive.ObjectID, retention time.Duration) error {
+	return nil
+}
+
+type boltRollupStore struct {
+	db *bbolt.DB
+}
+
+// bucketStartKey orders the rollups of a resolution bucket by bucket start
+func bucketStartKey(start time.Time) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(start.UnixNano()))
+}
+
+// resolutionBucket returns the nested bucket holding a monitor's rollups of
+// one resolution, or nil when there are none
+func resolutionBucket(tx *bbolt.Tx, monitorID primitive.ObjectID, resolution string) *bbolt.Bucket {
+	monitor := tx.Bucket(rollupsBucket).Bucket(monitorID[:])
+	if monitor == nil {
+		return nil
+	}
+	return monitor.Bucket([]byte(resolution))
+}
+
+func (s boltRollupStore) Save(rollups []models.MetricRollup) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		for _, rollup := range rollups {
+			monitor, err := tx.Bucket(rollupsBucket).CreateBucketIfNotExists(rollup.MonitorID[:])
+			if err != nil {
+				return err
+			}
+			bucket, err := monitor.CreateBucketIfNotExists([]byte(rollup.Resolution))
+			if err != nil {
+				return err
+			}
+
+			key := bucketStartKey(rollup.BucketStart)
+			if existing, err := getDoc[models.MetricRollup](bucket, key); err == nil {
+				rollup.ID = existing.ID
+			} else if rollup.ID.IsZero() {
+				rollup.ID = primitive.NewObjectID()
+			}
+			if err := putDoc(bucket, key, rollup); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s boltRollupStore) Since(monitorID primitive.ObjectID, resolution string, since time.Time) ([]models.MetricRollup, error) {
+	var rollups []models.MetricRollup
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bucket := resolutionBucket(tx, monitorID, resolution)
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		for k, v := c.Last(); k != nil && !timeOfKey(k).Before(since); k, v = c.Prev() {
+			var rollup models.MetricRollup
+			if err := bson.Unmarshal(v, &rollup); err != nil {
+				return err
+			}
+			rollups = append(rollups, rollup)
+		}
+		return nil
+	})
+	return rollups, err
+}
+
+func (s boltRollupStore) Latest(monitorID primitive.ObjectID, resolution string) (*models.MetricRollup, error) {
+	var rollup *models.MetricRollup
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bucket := resolutionBucket(tx, monitorID, resolution)
+		if bucket == nil {
+			return ErrNotFound
+		}
+
+		k, _ := bucket.Cursor().Last()
+		if k == nil {
+			return ErrNotFound
+		}
+
+		var err error
+		rollup, err = getDoc[models.MetricRollup](bucket, k)
+		return err
+	})
+	return rollup, err
+}
+
+type boltIncidentStore struct {
+	db *bbolt.DB
+}
+
+func (s boltIncidentStore) Insert(incident *models.Incident) error {
+	incident.ID = primitive.NewObjectID()
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		if err := putDoc(tx.Bucket(incidentsBucket), incident.ID[:], incident); err != nil {
+			return err
+		}
+		return indexIncident(tx, incident)
+	})
+}
+
+// indexIncident adds an incident to its monitor's incidents and, while it
+// is open, records it as the monitor's open incident
+func indexIncident(tx *bbolt.Tx, incident *models.Incident) error {
+	monitor, err := tx.Bucket(monitorIncidentsBucket).CreateBucketIfNotExists(incident.MonitorID[:])
+	if err != nil {
+		return err
+	}
+	if err := monitor.Put(incident.ID[:], []byte{}); err != nil {
+		return err
+	}
+
+	if incident.Status != "open" {
+		return nil
+	}
+	return tx.Bucket(openIncidentsBucket).Put(incident.MonitorID[:], incident.ID[:])
+}
+
+func (s boltIncidentStore) Get(id primitive.ObjectID) (*models.Incident, error) {
+	return viewDoc[models.Incident](s.db, incidentsBucket, id)
+}
+
+func (s boltIncidentStore) FindOpen(monitorID primitive.ObjectID) (*models.Incident, error) {
+	var incident *models.Incident
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		id := tx.Bucket(openIncidentsBucket).Get(monitorID[:])
+		if id == nil {
+			return ErrNotFound
+		}
+
+		var err error
+		incident, err = getDoc[models.Incident](tx.Bucket(incidentsBucket), id)
+		return err
+	})
+	return incident, err
+}
+
+func (s boltIncidentStore) RecordFailure(monitorID primitive.ObjectID, lastError string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		id := tx.Bucket(openIncidentsBucket).Get(monitorID[:])
+		if id == nil {
+			return ErrNotFound
+		}
+
+		bucket := tx.Bucket(incidentsBucket)
+		incident, err := getDoc[models.Incident](bucket, id)
+		if err != nil {
+			return err
+		}
+		incident.AffectedChecks++
+		incident.LastError = lastError
+		return putDoc(bucket, id, incident)
+	})
+}
+
+func (s boltIncidentStore) Resolve(id primitive.ObjectID, resolvedAt time.Time, duration int64) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(incidentsBucket)
+		incident, err := getDoc[models.Incident](bucket, id[:])
+		if err == ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		incident.Status = "resolved"
+		incident.ResolvedAt = &resolvedAt
+		incident.Duration = duration
+		if err := putDoc(bucket, id[:], incident); err != nil {
+			return err
+		}
+
+		open := tx.Bucket(openIncidentsBucket)
+		if bytes.Equal(open.Get(incident.MonitorID[:]), id[:]) {
+			return open.Delete(incident.MonitorID[:])
+		}
+		return nil
+	})
+}
+
+// errNotApplicable aborts an update whose document no longer matches its filter
+var errNotApplicable = errors.New("document does not match")
+
+func (s boltIncidentStore) Acknowledge(id primitive.ObjectID, acknowledgedBy string, at time.Time) (*models.Incident, error) {
+	incident, err := updateDoc(s.db, incidentsBucket, id, func(incident *models.Incident) error {
+		if incident.Status != "open" || incident.AcknowledgedAt != nil {
+			return errNotApplicable
+		}
+		incident.AcknowledgedAt = &at
+		incident.AcknowledgedBy = acknowledgedBy
+		return nil
+	})
+	if err == errNotApplicable {
+		return nil, ErrNotFound
+	}
+	return incident, err
+}
+
+func (s boltIncidentStore) AdvanceEscalation(id primitive.ObjectID, from, to int) (bool, error) {
+	_, err := updateDoc(s.db, incidentsBucket, id, func(incident *models.Incident) error {
+		if incident.Status != "open" || incident.AcknowledgedAt != nil || incident.EscalationLevel != from {
+			return errNotApplicable
+		}
+		incident.EscalationLevel = to
+		return nil
+	})
+	switch {
+	case err == errNotApplicable || err == ErrNotFound:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return from != to, nil
+}
+
+func (s boltIncidentStore) HoldEscalation(id primitive.ObjectID, at time.Time) (bool, error) {
+	_, err := updateDoc(s.db, incidentsBucket, id, func(incident *models.Incident) error {
+		if incident.Status != "open" || incident.AcknowledgedAt != nil || incident.EscalationHeldAt != nil {
+			return errNotApplicable
+		}
+		incident.EscalationHeldAt = &at
+		return nil
+	})
+	switch {
+	case err == errNotApplicable || err == ErrNotFound:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+func (s boltIncidentStore) ResumeEscalation(id primitive.ObjectID, startedAt time.Time) (bool, error) {
+	_, err := updateDoc(s.db, incidentsBucket, id, func(incident *models.Incident) error {
+		if incident.Status != "open" || incident.AcknowledgedAt != nil || incident.EscalationHeldAt == nil {
+			return errNotApplicable
+		}
+		incident.EscalationHeldAt = nil
+		incident.EscalationStartedAt = &startedAt
+		return nil
+	})
+	switch {
+	case err == errNotApplicable || err == ErrNotFound:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+func (s boltIncidentStore) DetachEscalationPolicy(policyID primitive.ObjectID) error {
+	return updateAll(s.db, incidentsBucket, func(incident *models.Incident) bool {
+		if incident.EscalationPolicyID == nil || *incident.EscalationPolicyID != policyID {
+			return false
+		}
+		incident.EscalationPolicyID = nil
+		return true
+	})
+}
+
+func (s boltIncidentStore) List(filter IncidentFilter, n int) ([]models.Incident, error) {
+	matched := []models.Incident{}
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(incidentsBucket)
+		return forEachCandidate(tx, filter, func(id []byte) error {
+			incident, err := getDoc[models.Incident](bucket, id)
+			if err != nil {
+				return err
+			}
+			if filter.matches(*incident) {
+				matched = append(matched, *incident)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortNewestFirst(matched)
+	return limit(matched, n), nil
+}
+
+// forEachCandidate calls fn with the ID of every incident that may match the
+// filter, using the indexes to skip those that cannot: only open incidents
+// can be open or escalating, and only a monitor's own incidents belong to it
+func forEachCandidate(tx *bbolt.Tx, filter IncidentFilter, fn func(id []byte) error) error {
+	openOnly := filter.Status == "open" || filter.Escalating
+	switch {
+	case openOnly && filter.MonitorID != nil:
+		if id := tx.Bucket(openIncidentsBucket).Get(filter.MonitorID[:]); id != nil {
+			return fn(id)
+		}
+		return nil
+	case openOnly:
+		return tx.Bucket(openIncidentsBucket).ForEach(func(_, id []byte) error {
+			return fn(id)
+		})
+	case filter.MonitorID != nil:
+		monitor := tx.Bucket(monitorIncidentsBucket).Bucket(filter.MonitorID[:])
+		if monitor == nil {
+			return nil
+		}
+		return monitor.ForEach(func(id, _ []byte) error {
+			return fn(id)
+		})
+	default:
+		return tx.Bucket(incidentsBucket).ForEach(func(id, _ []byte) error {
+			return fn(id)
+		})
+	}
+}
+
+type boltNotificationStore struct {
+	db *bbolt.DB
+}
+
+func (s boltNotificationStore) CreateChannel(channel *models.NotificationChannel) error {
+	channel.ID = primitive.NewObjectID()
+	return insertDoc(s.db, channelsBucket, channel.ID, channel)
+}
+
+func (s boltNotificationStore) ListChannels() ([]models.NotificationChannel, error) {
+	return viewDocs[models.NotificationChannel](s.db, channelsBucket)
+}
+
+func (s boltNotificationStore) GetChannel(id primitive.ObjectID) (*models.NotificationChannel, error) {
+	return viewDoc[models.NotificationChannel](s.db, channelsBucket, id)
+}
+
+func (s boltNotificationStore) DeleteChannel(id primitive.ObjectID) error {
+	return deleteDoc(s.db, channelsBucket, id)
+}
+
+func (s boltNotificationStore) CountChannels(ids []primitive.ObjectID) (int, error) {
+	unique := map[primitive.ObjectID]bool{}
+	for _, id := range ids {
+		unique[id] = true
+	}
+
+	count := 0
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(channelsBucket)
+		for id := range unique {
+			if bucket.Get(id[:]) != nil {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (s boltNotificationStore) InsertDelivery(delivery *models.NotificationDelivery) error {
+	delivery.ID = primitive.NewObjectID()
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return putDoc(tx.Bucket(deliveriesBucket), timeKey(delivery.CreatedAt, delivery.ID), delivery)
+	})
+}
+
+func (s boltNotificationStore) ListDeliveries(channelID *primitive.ObjectID, n int) ([]models.NotificationDelivery, error) {
+	deliveries := []models.NotificationDelivery{}
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket(deliveriesBucket).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var delivery models.NotificationDelivery
+			if err := bson.Unmarshal(v, &delivery); err != nil {
+				return err
+			}
+			if channelID != nil && delivery.ChannelID != *channelID {
+				continue
+			}
+			deliveries = append(deliveries, delivery)
+			if n > 0 && len(deliveries) == n {
+				break
+			}
+		}
+		return nil
+	})
+	return deliveries, err
+}
+
+type boltEscalationPolicyStore struct {
+	db *bbolt.DB
+}
+
+func (s boltEscalationPolicyStore) Create(policy *models.EscalationPolicy) error {
+	policy.ID = primitive.NewObjectID()
+	return insertDoc(s.db, policiesBucket, policy.ID, policy)
+}
+
+func (s boltEscalationPolicyStore) List() ([]models.EscalationPolicy, error) {
+	return viewDocs[models.EscalationPolicy](s.db, policiesBucket)
+}
+
+func (s boltEscalationPolicyStore) Get(id primitive.ObjectID) (*models.EscalationPolicy, error) {
+	return viewDoc[models.EscalationPolicy](s.db, policiesBucket, id)
+}
+
+func (s boltEscalationPolicyStore) Delete(id primitive.ObjectID) error {
+	return deleteDoc(s.db, policiesBucket, id)
+}
+
+type boltMaintenanceWindowStore struct {
+	db *bbolt.DB
+}
+
+func (s boltMaintenanceWindowStore) Create(window *models.MaintenanceWindow) error {
+	window.ID = primitive.NewObjectID()
+	return insertDoc(s.db, windowsBucket, window.ID, window)
+}
+
+func (s boltMaintenanceWindowStore) List() ([]models.MaintenanceWindow, error) {
+	return viewDocs[models.MaintenanceWindow](s.db, windowsBucket)
+}
+
+// ListForMonitor returns every window; there are few enough to leave the
+// matching to the caller
+func (s boltMaintenanceWindowStore) ListForMonitor(monitor models.Monitor, at time.Time) ([]models.MaintenanceWindow, error) {
+	return s.List()
+}
+
+func (s boltMaintenanceWindowStore) Delete(id primitive.ObjectID) error {
+	return deleteDoc(s.db, windowsBucket, id)
+}
@@ -0,0 +1,338 @@
+package database
+
+import (
+	"context"
+	"encoding/binary"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/models"
+)
+
+// openTestBoltStore opens a store in a fresh file that is closed when the test ends
+func openTestBoltStore(t testing.TB, path string) *BoltStore {
+	t.Helper()
+
+	store, err := OpenBoltStore(path, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("OpenBoltStore failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close(context.Background()) })
+	return store
+}
+
+// schemaVersion reads the schema version stored in the file
+func schemaVersion(t *testing.T, store *BoltStore) int {
+	t.Helper()
+
+	var version int
+	err := store.db.View(func(tx *bbolt.Tx) error {
+		version = int(binary.BigEndian.Uint64(tx.Bucket(metaBucket).Get(schemaVersionKey)))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to read schema version: %v", err)
+	}
+	return version
+}
+
+// writeSchemaVersion creates a file with only the first version migrations
+// applied, then lets fn add documents to it
+func writeSchemaVersion(t *testing.T, path string, version int, fn func(tx *bbolt.Tx) error) {
+	t.Helper()
+
+	db, err := bbolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatalf("failed to create %s: %v", path, err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		for _, migrate := range boltMigrations[:version] {
+			if err := migrate(tx); err != nil {
+				return err
+			}
+		}
+		if err := meta.Put(schemaVersionKey, binary.BigEndian.AppendUint64(nil, uint64(version))); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+	if err != nil {
+		t.Fatalf("failed to write version %d file: %v", version, err)
+	}
+}
+
+func TestBoltMigrationsFromVersion1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monitor.db")
+	monitorID := primitive.NewObjectID()
+	start := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+
+	// Two open incidents of one monitor, as an older build could leave
+	// behind, and a resolved one
+	older := models.Incident{ID: primitive.NewObjectID(), MonitorID: monitorID, Status: "open", StartedAt: start}
+	newer := models.Incident{ID: primitive.NewObjectID(), MonitorID: monitorID, Status: "open", StartedAt: start.Add(time.Minute)}
+	resolved := models.Incident{ID: primitive.NewObjectID(), MonitorID: monitorID, Status: "resolved", StartedAt: start.Add(-time.Hour)}
+	writeSchemaVersion(t, path, 1, func(tx *bbolt.Tx) error {
+		if tx.Bucket(rollupsBucket) != nil {
+			t.Error("version 1 file already has the rollups bucket")
+		}
+		for _, incident := range []models.Incident{newer, older, resolved} {
+			if err := putDoc(tx.Bucket(incidentsBucket), incident.ID[:], incident); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	store := openTestBoltStore(t, path)
+	if version := schemaVersion(t, store); version != len(boltMigrations) {
+		t.Fatalf("schema version = %d, want %d", version, len(boltMigrations))
+	}
+
+	// 2: rollups
+	rollup := models.MetricRollup{MonitorID: monitorID, Resolution: models.ResolutionHour, BucketStart: start.Truncate(time.Hour), Count: 1, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := store.Rollups().Save([]models.MetricRollup{rollup}); err != nil {
+		t.Fatalf("Save rollup after migration failed: %v", err)
+	}
+
+	// 3: incident indexes
+	open, err := store.Incidents().FindOpen(monitorID)
+	if err != nil || open.ID != newer.ID {
+		t.Fatalf("FindOpen after migration = %+v, %v, want the newest open incident", open, err)
+	}
+	incidents, err := store.Incidents().List(IncidentFilter{MonitorID: &monitorID}, 0)
+	if err != nil || len(incidents) != 3 {
+		t.Fatalf("monitor incidents after migration = %d, %v, want 3", len(incidents), err)
+	}
+}
+
+func TestBoltStoreReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monitor.db")
+	store, err := OpenBoltStore(path, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("OpenBoltStore failed: %v", err)
+	}
+
+	monitor := &models.Monitor{Name: "API", URL: "https://api.example.com"}
+	if err := store.Monitors().Create(monitor); err != nil {
+		t.Fatalf("failed to create monitor: %v", err)
+	}
+	checkedAt := time.Now().Truncate(time.Millisecond)
+	if err := store.Metrics().Insert(&models.Metric{MonitorID: monitor.ID, Status: "down", CheckedAt: checkedAt}); err != nil {
+		t.Fatalf("failed to insert metric: %v", err)
+	}
+	incident := &models.Incident{MonitorID: monitor.ID, Status: "open", StartedAt: checkedAt}
+	if err := store.Incidents().Insert(incident); err != nil {
+		t.Fatalf("failed to insert incident: %v", err)
+	}
+	if err := store.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reopened := openTestBoltStore(t, path)
+	if version := schemaVersion(t, reopened); version != len(boltMigrations) {
+		t.Errorf("schema version after reopening = %d, want %d", version, len(boltMigrations))
+	}
+	if stored, err := reopened.Monitors().Get(monitor.ID); err != nil || stored.Name != monitor.Name {
+		t.Errorf("monitor after reopening = %+v, %v", stored, err)
+	}
+	if metrics, err := reopened.Metrics().Since(monitor.ID, time.Time{}, 0); err != nil || len(metrics) != 1 || !metrics[0].CheckedAt.Equal(checkedAt) {
+		t.Errorf("metrics after reopening = %+v, %v, want the one inserted", metrics, err)
+	}
+	if open, err := reopened.Incidents().FindOpen(monitor.ID); err != nil || open.ID != incident.ID {
+		t.Errorf("open incident after reopening = %+v, %v", open, err)
+	}
+}
+
+func TestBoltStoreRejectsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monitor.db")
+	writeSchemaVersion(t, path, len(boltMigrations), func(tx *bbolt.Tx) error {
+		return tx.Bucket(metaBucket).Put(schemaVersionKey, binary.BigEndian.AppendUint64(nil, uint64(len(boltMigrations)+1)))
+	})
+
+	if _, err := OpenBoltStore(path, time.Hour); err == nil || !strings.Contains(err.Error(), "newer than this build supports") {
+		t.Fatalf("OpenBoltStore err = %v, want newer schema error", err)
+	}
+}
+
+func TestBoltStorePrune(t *testing.T) {
+	store := openTestBoltStore(t, filepath.Join(t.TempDir(), "monitor.db"))
+	now := time.Now()
+
+	// The default keeps a day of metrics; the second monitor keeps three
+	standard := &models.Monitor{Name: "standard", URL: "https://a.example.com"}
+	extended := &models.Monitor{Name: "extended", URL: "https://b.example.com", RetentionDays: 3}
+	for _, monitor := range []*models.Monitor{standard, extended} {
+		if err := store.Monitors().Create(monitor); err != nil {
+			t.Fatalf("failed to create monitor: %v", err)
+		}
+		for _, age := range []time.Duration{time.Hour, 2 * 24 * time.Hour, 4 * 24 * time.Hour} {
+			if err := store.Metrics().Insert(&models.Metric{MonitorID: monitor.ID, Status: "up", CheckedAt: now.Add(-age)}); err != nil {
+				t.Fatalf("failed to insert metric: %v", err)
+			}
+		}
+	}
+
+	rollups := []models.MetricRollup{
+		{MonitorID: standard.ID, Resolution: models.ResolutionMinute, BucketStart: now.Add(-2 * time.Hour).Truncate(time.Minute), Count: 1, ExpiresAt: now.Add(-time.Minute)},
+		{MonitorID: standard.ID, Resolution: models.ResolutionMinute, BucketStart: now.Add(-time.Hour).Truncate(time.Minute), Count: 1, ExpiresAt: now.Add(time.Hour)},
+	}
+	if err := store.Rollups().Save(rollups); err != nil {
+		t.Fatalf("failed to save rollups: %v", err)
+	}
+
+	for _, age := range []time.Duration{time.Hour, deliveryRetention + time.Hour} {
+		if err := store.Notifications().InsertDelivery(&models.NotificationDelivery{Status: "delivered", CreatedAt: now.Add(-age)}); err != nil {
+			t.Fatalf("failed to insert delivery: %v", err)
+		}
+	}
+
+	if err := store.prune(now); err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+
+	if metrics, err := store.Metrics().Since(standard.ID, time.Time{}, 0); err != nil || len(metrics) != 1 {
+		t.Errorf("metrics kept with the default retention = %d, %v, want 1", len(metrics), err)
+	}
+	if metrics, err := store.Metrics().Since(extended.ID, time.Time{}, 0); err != nil || len(metrics) != 2 {
+		t.Errorf("metrics kept with a 3 day retention = %d, %v, want 2", len(metrics), err)
+	}
+	if kept, err := store.Rollups().Since(standard.ID, models.ResolutionMinute, time.Time{}); err != nil || len(kept) != 1 || !kept[0].BucketStart.Equal(rollups[1].BucketStart) {
+		t.Errorf("rollups kept = %+v, %v, want the unexpired one", kept, err)
+	}
+	if deliveries, err := store.Notifications().ListDeliveries(nil, 0); err != nil || len(deliveries) != 1 {
+		t.Errorf("deliveries kept = %d, %v, want 1", len(deliveries), err)
+	}
+}
+
+func TestBoltIncidentIndexes(t *testing.T) {
+	store := openTestBoltStore(t, filepath.Join(t.TempDir(), "monitor.db"))
+	incidents := store.Incidents()
+	monitorID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+	policyID := primitive.NewObjectID()
+	start := time.Now().Add(-time.Hour)
+
+	first := &models.Incident{MonitorID: monitorID, Status: "open", StartedAt: start}
+	other := &models.Incident{MonitorID: otherID, Status: "open", StartedAt: start, EscalationPolicyID: &policyID}
+	for _, incident := range []*models.Incident{first, other} {
+		if err := incidents.Insert(incident); err != nil {
+			t.Fatalf("failed to insert incident: %v", err)
+		}
+	}
+
+	if err := incidents.RecordFailure(monitorID, "timeout"); err != nil {
+		t.Fatalf("RecordFailure failed: %v", err)
+	}
+	if stored, err := incidents.Get(first.ID); err != nil || stored.AffectedChecks != 1 || stored.LastError != "timeout" {
+		t.Fatalf("incident after RecordFailure = %+v, %v", stored, err)
+	}
+
+	if err := incidents.Resolve(first.ID, start.Add(time.Minute), 60); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if _, err := incidents.FindOpen(monitorID); err != ErrNotFound {
+		t.Fatalf("FindOpen after Resolve err = %v, want ErrNotFound", err)
+	}
+	if err := incidents.RecordFailure(monitorID, "timeout"); err != ErrNotFound {
+		t.Fatalf("RecordFailure without an open incident err = %v, want ErrNotFound", err)
+	}
+
+	second := &models.Incident{MonitorID: monitorID, Status: "open", StartedAt: start.Add(2 * time.Minute)}
+	if err := incidents.Insert(second); err != nil {
+		t.Fatalf("failed to insert incident: %v", err)
+	}
+
+	// Resolving an incident that is no longer the open one leaves the index alone
+	if err := incidents.Resolve(first.ID, start.Add(time.Minute), 60); err != nil {
+		t.Fatalf("second Resolve failed: %v", err)
+	}
+	if open, err := incidents.FindOpen(monitorID); err != nil || open.ID != second.ID {
+		t.Fatalf("FindOpen = %+v, %v, want the second incident", open, err)
+	}
+
+	tests := []struct {
+		name   string
+		filter IncidentFilter
+		want   []primitive.ObjectID
+	}{
+		{"all", IncidentFilter{}, []primitive.ObjectID{second.ID, first.ID, other.ID}},
+		{"open", IncidentFilter{Status: "open"}, []primitive.ObjectID{second.ID, other.ID}},
+		{"resolved", IncidentFilter{Status: "resolved"}, []primitive.ObjectID{first.ID}},
+		{"monitor", IncidentFilter{MonitorID: &monitorID}, []primitive.ObjectID{second.ID, first.ID}},
+		{"monitor open", IncidentFilter{MonitorID: &monitorID, Status: "open"}, []primitive.ObjectID{second.ID}},
+		{"monitor resolved", IncidentFilter{MonitorID: &monitorID, Status: "resolved"}, []primitive.ObjectID{first.ID}},
+		{"escalating", IncidentFilter{Escalating: true}, []primitive.ObjectID{other.ID}},
+		{"unknown monitor", IncidentFilter{MonitorID: &policyID}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed, err := incidents.List(tt.filter, 0)
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(listed) != len(tt.want) {
+				t.Fatalf("List = %d incidents, want %d", len(listed), len(tt.want))
+			}
+			for i, id := range tt.want {
+				if listed[i].ID != id {
+					t.Errorf("incident %d = %s, want %s", i, listed[i].ID.Hex(), id.Hex())
+				}
+			}
+		})
+	}
+}
+
+func TestBoltAdvanceEscalationIsAtomic(t *testing.T) {
+	store := openTestBoltStore(t, filepath.Join(t.TempDir(), "monitor.db"))
+	policyID := primitive.NewObjectID()
+	incident := &models.Incident{MonitorID: primitive.NewObjectID(), Status: "open", StartedAt: time.Now(), EscalationPolicyID: &policyID}
+	if err := store.Incidents().Insert(incident); err != nil {
+		t.Fatalf("failed to insert incident: %v", err)
+	}
+
+	// Concurrent sweeps race to notify the same step; only one may win
+	const sweeps = 20
+	var wg sync.WaitGroup
+	results := make(chan bool, sweeps)
+	for i := 0; i < sweeps; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			advanced, err := store.Incidents().AdvanceEscalation(incident.ID, 0, 1)
+			if err != nil {
+				t.Errorf("AdvanceEscalation failed: %v", err)
+			}
+			results <- advanced
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	winners := 0
+	for advanced := range results {
+		if advanced {
+			winners++
+		}
+	}
+	if winners != 1 {
+		t.Fatalf("%d sweeps advanced the escalation, want exactly 1", winners)
+	}
+	if stored, err := store.Incidents().Get(incident.ID); err != nil || stored.EscalationLevel != 1 {
+		t.Fatalf("incident after racing sweeps = %+v, %v, want level 1", stored, err)
+	}
+
+	if advanced, err := store.Incidents().AdvanceEscalation(incident.ID, 0, 1); err != nil || advanced {
+		t.Errorf("AdvanceEscalation from a stale level = %v, %v, want false", advanced, err)
+	}
+}
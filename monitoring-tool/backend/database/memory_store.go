@@ -0,0 +1,839 @@
+// database/memory_store.go
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/models"
+)
+
+// MemoryStore keeps everything in process memory. Nothing survives a
+// restart, so it is meant for development, demos and tests.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	retention time.Duration // how long metrics are kept unless the monitor overrides it
+
+	monitors           []models.Monitor
+	metrics            map[primitive.ObjectID][]models.Metric // per monitor, oldest first
+	rollups            map[rollupKey][]models.MetricRollup    // oldest first
+	incidents          []models.Incident
+	channels           []models.NotificationChannel
+	deliveries         []models.NotificationDelivery // oldest first
+	escalationPolicies []models.EscalationPolicy
+	maintenanceWindows []models.MaintenanceWindow
+}
+
+// NewMemoryStore creates an empty in-memory store that keeps metrics for
+// retention unless a monitor sets its own
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	return &MemoryStore{
+		retention: retention,
+		metrics:   map[primitive.ObjectID][]models.Metric{},
+		rollups:   map[rollupKey][]models.MetricRollup{},
+	}
+}
+
+func (s *MemoryStore) Monitors() MonitorStore {
+	return memoryMonitorStore{s}
+}
+
+func (s *MemoryStore) Metrics() MetricStore {
+	return memoryMetricStore{s}
+}
+
+func (s *MemoryStore) Rollups() RollupStore {
+	return memoryRollupStore{s}
+}
+
+func (s *MemoryStore) Incidents() IncidentStore {
+	return memoryIncidentStore{s}
+}
+
+func (s *MemoryStore) Notifications() NotificationStore {
+	return memoryNotificationStore{s}
+}
+
+func (s *MemoryStore) EscalationPolicies() EscalationPolicyStore {
+	return memoryEscalationPolicyStore{s}
+}
+
+func (s *MemoryStore) MaintenanceWindows() MaintenanceWindowStore {
+	return memoryMaintenanceWindowStore{s}
+}
+
+// Health always succeeds for the in-memory store
+func (s *MemoryStore) Health() error {
+	return nil
+}
+
+// Close is a no-op for the in-memory store
+func (s *MemoryStore) Close(ctx context.Context) error {
+	return nil
+}
+
+// clone deep-copies a document through BSON, so callers never share slices
+// or maps with the store and see the same field handling as with MongoDB
+func clone[T any](doc T) (T, error) {
+	var out T
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return out, err
+	}
+	err = bson.Unmarshal(data, &out)
+	return out, err
+}
+
+// cloneAll deep-copies a list of documents
+func cloneAll[T any](docs []T) ([]T, error) {
+	out := make([]T, 0, len(docs))
+	for _, doc := range docs {
+		copied, err := clone(doc)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, copied)
+	}
+	return out, nil
+}
+
+// limit truncates docs to n entries. A limit of 0 keeps every entry.
+func limit[T any](docs []T, n int) []T {
+	if n > 0 && len(docs) > n {
+		return docs[:n]
+	}
+	return docs
+}
+
+type memoryMonitorStore struct {
+	s *MemoryStore
+}
+
+// find returns the index of the monitor, or -1. The caller holds the lock.
+func (m memoryMonitorStore) find(id primitive.ObjectID) int {
+	for i := range m.s.monitors {
+		if m.s.monitors[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m memoryMonitorStore) Create(monitor *models.Monitor) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	monitor.ID = primitive.NewObjectID()
+	stored, err := clone(*monitor)
+	if err != nil {
+		return err
+	}
+	m.s.monitors = append(m.s.monitors, stored)
+	return nil
+}
+
+func (m memoryMonitorStore) List() ([]models.Monitor, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	return cloneAll(m.s.monitors)
+}
+
+func (m memoryMonitorStore) Get(id primitive.ObjectID) (*models.Monitor, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	return m.get(id)
+}
+
+// get returns a copy of the monitor. The caller holds the lock.
+func (m memoryMonitorStore) get(id primitive.ObjectID) (*models.Monitor, error) {
+	i := m.find(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	monitor, err := clone(m.s.monitors[i])
+	if err != nil {
+		return nil, err
+	}
+	return &monitor, nil
+}
+
+func (m memoryMonitorStore) TargetExists(target *models.Monitor, excludeID *primitive.ObjectID) (bool, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	for i := range m.s.monitors {
+		monitor := &m.s.monitors[i]
+		if sameTarget(monitor, target) && (excludeID == nil || monitor.ID != *excludeID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m memoryMonitorStore) UpdateSettings(monitor *models.Monitor) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	i := m.find(monitor.ID)
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	settings, err := clone(*monitor)
+	if err != nil {
+		return err
+	}
+	applySettings(&m.s.monitors[i], settings)
+	return nil
+}
+
+func (m memoryMonitorStore) Pause(id primitive.ObjectID, pausedBy, reason string, at time.Time) (*models.Monitor, error) {
+	return m.update(id, func(monitor *models.Monitor) {
+		pause(monitor, pausedBy, reason, at)
+	})
+}
+
+func (m memoryMonitorStore) Resume(id primitive.ObjectID, at time.Time) (*models.Monitor, error) {
+	return m.update(id, func(monitor *models.Monitor) {
+		resume(monitor, at)
+	})
+}
+
+// update applies fn to the stored monitor and returns a copy of the result
+func (m memoryMonitorStore) update(id primitive.ObjectID, fn func(*models.Monitor)) (*models.Monitor, error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	i := m.find(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	fn(&m.s.monitors[i])
+	return m.get(id)
+}
+
+func (m memoryMonitorStore) Delete(id primitive.ObjectID) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	i := m.find(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	m.s.monitors = append(m.s.monitors[:i], m.s.monitors[i+1:]...)
+
+	// Metrics are otherwise only pruned when the monitor records a new one
+	delete(m.s.metrics, id)
+	for _, resolution := range models.Resolutions {
+		delete(m.s.rollups, rollupKey{id, resolution})
+	}
+	return nil
+}
+
+func (m memoryMonitorStore) UpdateStatus(id primitive.ObjectID, status string, statusCode int, responseTime int64, checkedAt time.Time) (string, error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	i := m.find(id)
+	if i < 0 {
+		return "", ErrNotFound
+	}
+
+	monitor := &m.s.monitors[i]
+	previous := monitor.CurrentStatus
+	monitor.CurrentStatus = status
+	monitor.CurrentResponse = int(responseTime)
+	monitor.LastChecked = &checkedAt
+	monitor.UpdatedAt = time.Now()
+
+	return previous, nil
+}
+
+func (m memoryMonitorStore) UpdateCertificate(id primitive.ObjectID, info *models.TLSInfo) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	if i := m.find(id); i >= 0 {
+		certificate := *info
+		m.s.monitors[i].Certificate = &certificate
+	}
+	return nil
+}
+
+func (m memoryMonitorStore) SetFlapping(id primitive.ObjectID, flapping bool) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	if i := m.find(id); i >= 0 {
+		m.s.monitors[i].IsFlapping = flapping
+	}
+	return nil
+}
+
+func (m memoryMonitorStore) ClearFlapping() error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	for i := range m.s.monitors {
+		m.s.monitors[i].IsFlapping = false
+	}
+	return nil
+}
+
+func (m memoryMonitorStore) DetachEscalationPolicy(policyID primitive.ObjectID) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	for i := range m.s.monitors {
+		if id := m.s.monitors[i].EscalationPolicyID; id != nil && *id == policyID {
+			m.s.monitors[i].EscalationPolicyID = nil
+		}
+	}
+	return nil
+}
+
+type memoryMetricStore struct {
+	s *MemoryStore
+}
+
+func (m memoryMetricStore) Insert(metric *models.Metric) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	metric.ID = primitive.NewObjectID()
+	stored, err := clone(*metric)
+	if err != nil {
+		return err
+	}
+
+	// Drop metrics past the monitor's retention
+	retention := m.s.retention
+	if i := (memoryMonitorStore{m.s}).find(metric.MonitorID); i >= 0 {
+		retention = m.s.monitors[i].MetricsRetention(retention)
+	}
+	metrics := m.s.metrics[metric.MonitorID]
+	cutoff := time.Now().Add(-retention)
+	expired := sort.Search(len(metrics), func(i int) bool { return !metrics[i].CheckedAt.Before(cutoff) })
+
+	m.s.metrics[metric.MonitorID] = append(metrics[expired:], stored)
+	return nil
+}
+
+func (m memoryMetricStore) Since(monitorID primitive.ObjectID, since time.Time, n int) ([]models.Metric, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	var matched []models.Metric
+	metrics := m.s.metrics[monitorID]
+	for i := len(metrics) - 1; i >= 0; i-- {
+		if metrics[i].CheckedAt.Before(since) {
+			break
+		}
+		matched = append(matched, metrics[i])
+		if n > 0 && len(matched) == n {
+			break
+		}
+	}
+
+	if matched == nil {
+		return nil, nil
+	}
+	return cloneAll(matched)
+}
+
+func (m memoryMetricStore) Between(monitorID primitive.ObjectID, from, to time.Time) ([]models.Metric, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	metrics := m.s.metrics[monitorID]
+	first := sort.Search(len(metrics), func(i int) bool { return !metrics[i].CheckedAt.Before(from) })
+	last := sort.Search(len(metrics), func(i int) bool { return !metrics[i].CheckedAt.Before(to) })
+	if first >= last {
+		return nil, nil
+	}
+	return cloneAll(metrics[first:last])
+}
+
+func (m memoryMetricStore) CountSince(monitorID primitive.ObjectID, since time.Time) (MetricCounts, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	var counts MetricCounts
+	metrics := m.s.metrics[monitorID]
+	for i := len(metrics) - 1; i >= 0 && !metrics[i].CheckedAt.Before(since); i-- {
+		counts.add(metrics[i].Status, metrics[i].InMaintenance)
+	}
+	return counts, nil
+}
+
+// SetRetention does nothing: the retention is looked up whenever metrics are pruned
+func (m memoryMetricStore) PerMonitorRetention() bool {
+	return true
+}
+
+func (m memoryMetricStore) SetRetention(monitorID primitive.ObjectID, retention time.Duration) error {
+	return nil
+}
+
+// rollupKey identifies the rollups of one monitor at one resolution
+type rollupKey struct {
+	monitorID  primitive.ObjectID
+	resolution string
+}
+
+type memoryRollupStore struct {
+	s *MemoryStore
+}
+
+func (m memoryRollupStore) Save(rollups []models.MetricRollup) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	now := time.Now()
+	for _, rollup := range rollups {
+		stored, err := clone(rollup)
+		if err != nil {
+			return err
+		}
+		if stored.ID.IsZero() {
+			stored.ID = primitive.NewObjectID()
+		}
+
+		key := rollupKey{rollup.MonitorID, rollup.Resolution}
+		existing := m.s.rollups[key]
+		i := sort.Search(len(existing), func(i int) bool { return !existing[i].BucketStart.Before(rollup.BucketStart) })
+		if i < len(existing) && existing[i].BucketStart.Equal(rollup.BucketStart) {
+			existing[i] = stored
+		} else {
+			existing = append(existing[:i], append([]models.MetricRollup{stored}, existing[i:]...)...)
+		}
+
+		// Drop rollups that the TTL index would have expired
+		expired := sort.Search(len(existing), func(i int) bool { return existing[i].ExpiresAt.After(now) })
+		m.s.rollups[key] = existing[expired:]
+	}
+	return nil
+}
+
+func (m memoryRollupStore) Since(monitorID primitive.ObjectID, resolution string, since time.Time) ([]models.MetricRollup, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	var matched []models.MetricRollup
+	rollups := m.s.rollups[rollupKey{monitorID, resolution}]
+	for i := len(rollups) - 1; i >= 0 && !rollups[i].BucketStart.Before(since); i-- {
+		matched = append(matched, rollups[i])
+	}
+
+	if matched == nil {
+		return nil, nil
+	}
+	return cloneAll(matched)
+}
+
+func (m memoryRollupStore) Latest(monitorID primitive.ObjectID, resolution string) (*models.MetricRollup, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	rollups := m.s.rollups[rollupKey{monitorID, resolution}]
+	if len(rollups) == 0 {
+		return nil, ErrNotFound
+	}
+
+	rollup, err := clone(rollups[len(rollups)-1])
+	if err != nil {
+		return nil, err
+	}
+	return &rollup, nil
+}
+
+type memoryIncidentStore struct {
+	s *MemoryStore
+}
+
+// find returns the index of the first incident matching fn, or -1. The
+// caller holds the lock.
+func (m memoryIncidentStore) find(fn func(*models.Incident) bool) int {
+	for i := range m.s.incidents {
+		if fn(&m.s.incidents[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// get returns a copy of the incident at index i, or ErrNotFound when i is -1
+func (m memoryIncidentStore) get(i int) (*models.Incident, error) {
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	incident, err := clone(m.s.incidents[i])
+	if err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+// openFor matches the open incident of a monitor
+func openFor(monitorID primitive.ObjectID) func(*models.Incident) bool {
+	return func(incident *models.Incident) bool {
+		return incident.MonitorID == monitorID && incident.Status == "open"
+	}
+}
+
+func (m memoryIncidentStore) Insert(incident *models.Incident) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	incident.ID = primitive.NewObjectID()
+	stored, err := clone(*incident)
+	if err != nil {
+		return err
+	}
+	m.s.incidents = append(m.s.incidents, stored)
+	return nil
+}
+
+func (m memoryIncidentStore) Get(id primitive.ObjectID) (*models.Incident, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	return m.get(m.find(func(incident *models.Incident) bool { return incident.ID == id }))
+}
+
+func (m memoryIncidentStore) FindOpen(monitorID primitive.ObjectID) (*models.Incident, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	return m.get(m.find(openFor(monitorID)))
+}
+
+func (m memoryIncidentStore) RecordFailure(monitorID primitive.ObjectID, lastError string) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	i := m.find(openFor(monitorID))
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	m.s.incidents[i].AffectedChecks++
+	m.s.incidents[i].LastError = lastError
+	return nil
+}
+
+func (m memoryIncidentStore) Resolve(id primitive.ObjectID, resolvedAt time.Time, duration int64) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	if i := m.find(func(incident *models.Incident) bool { return incident.ID == id }); i >= 0 {
+		m.s.incidents[i].Status = "resolved"
+		m.s.incidents[i].ResolvedAt = &resolvedAt
+		m.s.incidents[i].Duration = duration
+	}
+	return nil
+}
+
+// unacknowledged matches an open incident nobody has acknowledged yet
+func unacknowledged(id primitive.ObjectID) func(*models.Incident) bool {
+	return func(incident *models.Incident) bool {
+		return incident.ID == id && incident.Status == "open" && incident.AcknowledgedAt == nil
+	}
+}
+
+func (m memoryIncidentStore) Acknowledge(id primitive.ObjectID, acknowledgedBy string, at time.Time) (*models.Incident, error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	i := m.find(unacknowledged(id))
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	m.s.incidents[i].AcknowledgedAt = &at
+	m.s.incidents[i].AcknowledgedBy = acknowledgedBy
+	return m.get(i)
+}
+
+func (m memoryIncidentStore) AdvanceEscalation(id primitive.ObjectID, from, to int) (bool, error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	i := m.find(unacknowledged(id))
+	if i < 0 || m.s.incidents[i].EscalationLevel != from {
+		return false, nil
+	}
+
+	m.s.incidents[i].EscalationLevel = to
+	return from != to, nil
+}
+
+func (m memoryIncidentStore) HoldEscalation(id primitive.ObjectID, at time.Time) (bool, error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	i := m.find(unacknowledged(id))
+	if i < 0 || m.s.incidents[i].EscalationHeldAt != nil {
+		return false, nil
+	}
+
+	m.s.incidents[i].EscalationHeldAt = &at
+	return true, nil
+}
+
+func (m memoryIncidentStore) ResumeEscalation(id primitive.ObjectID, startedAt time.Time) (bool, error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	i := m.find(unacknowledged(id))
+	if i < 0 || m.s.incidents[i].EscalationHeldAt == nil {
+		return false, nil
+	}
+
+	m.s.incidents[i].EscalationHeldAt = nil
+	m.s.incidents[i].EscalationStartedAt = &startedAt
+	return true, nil
+}
+
+func (m memoryIncidentStore) DetachEscalationPolicy(policyID primitive.ObjectID) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	for i := range m.s.incidents {
+		if id := m.s.incidents[i].EscalationPolicyID; id != nil && *id == policyID {
+			m.s.incidents[i].EscalationPolicyID = nil
+		}
+	}
+	return nil
+}
+
+func (m memoryIncidentStore) List(filter IncidentFilter, n int) ([]models.Incident, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	matched := []models.Incident{}
+	for _, incident := range m.s.incidents {
+		if filter.matches(incident) {
+			matched = append(matched, incident)
+		}
+	}
+
+	sortNewestFirst(matched)
+	return cloneAll(limit(matched, n))
+}
+
+type memoryNotificationStore struct {
+	s *MemoryStore
+}
+
+func (m memoryNotificationStore) CreateChannel(channel *models.NotificationChannel) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	channel.ID = primitive.NewObjectID()
+	stored, err := clone(*channel)
+	if err != nil {
+		return err
+	}
+	m.s.channels = append(m.s.channels, stored)
+	return nil
+}
+
+func (m memoryNotificationStore) ListChannels() ([]models.NotificationChannel, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	return cloneAll(m.s.channels)
+}
+
+func (m memoryNotificationStore) GetChannel(id primitive.ObjectID) (*models.NotificationChannel, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	for _, channel := range m.s.channels {
+		if channel.ID == id {
+			copied, err := clone(channel)
+			if err != nil {
+				return nil, err
+			}
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m memoryNotificationStore) DeleteChannel(id primitive.ObjectID) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	for i, channel := range m.s.channels {
+		if channel.ID == id {
+			m.s.channels = append(m.s.channels[:i], m.s.channels[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (m memoryNotificationStore) CountChannels(ids []primitive.ObjectID) (int, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	wanted := map[primitive.ObjectID]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	count := 0
+	for _, channel := range m.s.channels {
+		if wanted[channel.ID] {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m memoryNotificationStore) InsertDelivery(delivery *models.NotificationDelivery) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	delivery.ID = primitive.NewObjectID()
+	stored, err := clone(*delivery)
+	if err != nil {
+		return err
+	}
+
+	// Drop deliveries that the TTL index would have expired
+	cutoff := time.Now().Add(-deliveryRetention)
+	expired := sort.Search(len(m.s.deliveries), func(i int) bool { return !m.s.deliveries[i].CreatedAt.Before(cutoff) })
+
+	m.s.deliveries = append(m.s.deliveries[expired:], stored)
+	return nil
+}
+
+func (m memoryNotificationStore) ListDeliveries(channelID *primitive.ObjectID, n int) ([]models.NotificationDelivery, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	matched := []models.NotificationDelivery{}
+	for i := len(m.s.deliveries) - 1; i >= 0; i-- {
+		if channelID != nil && m.s.deliveries[i].ChannelID != *channelID {
+			continue
+		}
+		matched = append(matched, m.s.deliveries[i])
+	}
+
+	// Deliveries are logged when they complete, which is not always the
+	// order they were created in
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+	return cloneAll(limit(matched, n))
+}
+
+type memoryEscalationPolicyStore struct {
+	s *MemoryStore
+}
+
+func (m memoryEscalationPolicyStore) Create(policy *models.EscalationPolicy) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	policy.ID = primitive.NewObjectID()
+	stored, err := clone(*policy)
+	if err != nil {
+		return err
+	}
+	m.s.escalationPolicies = append(m.s.escalationPolicies, stored)
+	return nil
+}
+
+func (m memoryEscalationPolicyStore) List() ([]models.EscalationPolicy, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	return cloneAll(m.s.escalationPolicies)
+}
+
+func (m memoryEscalationPolicyStore) Get(id primitive.ObjectID) (*models.EscalationPolicy, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	for _, policy := range m.s.escalationPolicies {
+		if policy.ID == id {
+			copied, err := clone(policy)
+			if err != nil {
+				return nil, err
+			}
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m memoryEscalationPolicyStore) Delete(id primitive.ObjectID) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	for i, policy := range m.s.escalationPolicies {
+		if policy.ID == id {
+			m.s.escalationPolicies = append(m.s.escalationPolicies[:i], m.s.escalationPolicies[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+type memoryMaintenanceWindowStore struct {
+	s *MemoryStore
+}
+
+func (m memoryMaintenanceWindowStore) Create(window *models.MaintenanceWindow) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	window.ID = primitive.NewObjectID()
+	stored, err := clone(*window)
+	if err != nil {
+		return err
+	}
+	m.s.maintenanceWindows = append(m.s.maintenanceWindows, stored)
+	return nil
+}
+
+func (m memoryMaintenanceWindowStore) List() ([]models.MaintenanceWindow, error) {
+	m.s.mu.RLock()
+	defer m.s.mu.RUnlock()
+
+	return cloneAll(m.s.maintenanceWindows)
+}
+
+// ListForMonitor returns every window; there are few enough to leave the
+// matching to the caller
+func (m memoryMaintenanceWindowStore) ListForMonitor(monitor models.Monitor, at time.Time) ([]models.MaintenanceWindow, error) {
+	return m.List()
+}
+
+func (m memoryMaintenanceWindowStore) Delete(id primitive.ObjectID) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	for i, window := range m.s.maintenanceWindows {
+		if window.ID == id {
+			m.s.maintenanceWindows = append(m.s.maintenanceWindows[:i], m.s.maintenanceWindows[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"monitoring-tool/models"
+)
+
+func TestMemoryStoreDeleteFreesMetrics(t *testing.T) {
+	store := NewMemoryStore(24 * time.Hour)
+
+	monitors := []*models.Monitor{{Name: "kept", URL: "https://a.example.com"}, {Name: "deleted", URL: "https://b.example.com"}}
+	now := time.Now()
+	for _, monitor := range monitors {
+		if err := store.Monitors().Create(monitor); err != nil {
+			t.Fatalf("failed to create monitor: %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			metric := &models.Metric{MonitorID: monitor.ID, Status: "up", CheckedAt: now.Add(-time.Duration(i) * time.Minute)}
+			if err := store.Metrics().Insert(metric); err != nil {
+				t.Fatalf("failed to insert metric: %v", err)
+			}
+		}
+		rollup := models.MetricRollup{MonitorID: monitor.ID, Resolution: models.ResolutionMinute, BucketStart: now.Truncate(time.Minute), Count: 1, ExpiresAt: now.Add(time.Hour)}
+		if err := store.Rollups().Save([]models.MetricRollup{rollup}); err != nil {
+			t.Fatalf("failed to save rollup: %v", err)
+		}
+	}
+
+	kept, deleted := monitors[0], monitors[1]
+	if err := store.Monitors().Delete(deleted.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if _, ok := store.metrics[deleted.ID]; ok {
+		t.Error("metrics of the deleted monitor are still held")
+	}
+	if _, ok := store.rollups[rollupKey{deleted.ID, models.ResolutionMinute}]; ok {
+		t.Error("rollups of the deleted monitor are still held")
+	}
+	if metrics, err := store.Metrics().Since(kept.ID, time.Time{}, 0); err != nil || len(metrics) != 3 {
+		t.Errorf("metrics of the kept monitor = %d, %v, want 3", len(metrics), err)
+	}
+	if rollups, err := store.Rollups().Since(kept.ID, models.ResolutionMinute, time.Time{}); err != nil || len(rollups) != 1 {
+		t.Errorf("rollups of the kept monitor = %d, %v, want 1", len(rollups), err)
+	}
+}
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"monitoring-tool/models"
+)
+
+// Benchmarked stores hold benchMonitors monitors with benchChecks checks
+// each, spaced benchInterval apart and ending now
+const (
+	benchMonitors = 10
+	benchChecks   = 1000
+	benchInterval = 30 * time.Second
+)
+
+// benchMetricStores opens each store to benchmark. MongoDB is only
+// benchmarked when BENCH_MONGODB_URI is set, in scratch collections of the
+// monitoring_bench database.
+func benchMetricStores(b *testing.B) map[string]func(b *testing.B) MetricStore {
+	stores := map[string]func(b *testing.B) MetricStore{
+		"memory": func(b *testing.B) MetricStore { return NewMemoryStore(24 * time.Hour).Metrics() },
+		"bolt": func(b *testing.B) MetricStore {
+			return openTestBoltStore(b, filepath.Join(b.TempDir(), "monitor.db")).Metrics()
+		},
+	}
+
+	uri := os.Getenv("BENCH_MONGODB_URI")
+	if uri == "" {
+		return stores
+	}
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		b.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+	b.Cleanup(func() { client.Disconnect(ctx) })
+	db := client.Database("monitoring_bench")
+
+	for _, timeSeries := range []bool{false, true} {
+		name := "mongo regular"
+		if timeSeries {
+			name = "mongo timeseries"
+		}
+		timeSeries := timeSeries
+		stores[name] = func(b *testing.B) MetricStore {
+			collection := db.Collection("bench_metrics")
+			if err := collection.Drop(ctx); err != nil {
+				b.Fatalf("failed to drop %s: %v", collection.Name(), err)
+			}
+			if timeSeries {
+				err = CreateTimeSeriesCollection(ctx, db, collection.Name(), 24*time.Hour)
+			} else {
+				err = db.CreateCollection(ctx, collection.Name())
+			}
+			if err != nil {
+				b.Fatalf("failed to create %s: %v", collection.Name(), err)
+			}
+			if _, err := collection.Indexes().CreateMany(ctx, MetricsIndexes(timeSeries)); err != nil {
+				b.Fatalf("failed to create indexes: %v", err)
+			}
+			b.Cleanup(func() { collection.Drop(ctx) })
+			return &mongoMetricStore{collection: collection, timeSeries: timeSeries}
+		}
+	}
+	return stores
+}
+
+// fillMetrics inserts the checks of benchMonitors monitors in the order
+// they would have been written and returns the monitor IDs
+func fillMetrics(b *testing.B, store MetricStore, end time.Time) []primitive.ObjectID {
+	b.Helper()
+
+	random := rand.New(rand.NewSource(1))
+	ids := make([]primitive.ObjectID, benchMonitors)
+	for i := range ids {
+		ids[i] = primitive.NewObjectID()
+	}
+
+	start := end.Add(-benchChecks * benchInterval)
+	for c := 0; c < benchChecks; c++ {
+		for _, id := range ids {
+			if err := store.Insert(benchMetric(random, id, start.Add(time.Duration(c)*benchInterval))); err != nil {
+				b.Fatalf("failed to insert metric: %v", err)
+			}
+		}
+	}
+	return ids
+}
+
+// benchMetric returns a check that is down about once in a hundred
+func benchMetric(random *rand.Rand, monitorID primitive.ObjectID, checkedAt time.Time) *models.Metric {
+	status, code := "up", 200
+	if random.Intn(100) == 0 {
+		status, code = "down", 503
+	}
+	return &models.Metric{
+		MonitorID:    monitorID,
+		URL:          "https://example.com/" + monitorID.Hex(),
+		Status:       status,
+		StatusCode:   code,
+		ResponseTime: int64(50 + random.Intn(400)),
+		CheckedAt:    checkedAt,
+	}
+}
+
+func BenchmarkMetricInsert(b *testing.B) {
+	for name, open := range benchMetricStores(b) {
+		b.Run(name, func(b *testing.B) {
+			store := open(b)
+			random := rand.New(rand.NewSource(1))
+			ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+			start := time.Now().Add(-time.Hour)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// Stores expect checks in the order they happened
+				checkedAt := start.Add(time.Duration(i) * time.Millisecond)
+				if err := store.Insert(benchMetric(random, ids[i%len(ids)], checkedAt)); err != nil {
+					b.Fatalf("failed to insert metric: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMetricQueries(b *testing.B) {
+	for name, open := range benchMetricStores(b) {
+		b.Run(name, func(b *testing.B) {
+			store := open(b)
+			now := time.Now()
+			ids := fillMetrics(b, store, now)
+			since := now.Add(-time.Hour) // 120 of the monitor's checks
+
+			b.Run("since", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := store.Since(ids[i%len(ids)], since, 0); err != nil {
+						b.Fatalf("Since failed: %v", err)
+					}
+				}
+			})
+			b.Run("between", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := store.Between(ids[i%len(ids)], since.Add(-time.Hour), since); err != nil {
+						b.Fatalf("Between failed: %v", err)
+					}
+				}
+			})
+			b.Run("count since", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := store.CountSince(ids[i%len(ids)], since); err != nil {
+						b.Fatalf("CountSince failed: %v", err)
+					}
+				}
+			})
+		})
+	}
+}
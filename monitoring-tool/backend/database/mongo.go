@@ -3,26 +3,62 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"monitoring-tool/config"
+	"monitoring-tool/models"
 )
 
 // MongoDB holds the database connection
 type MongoDB struct {
 	Client   *mongo.Client
 	Database *mongo.Database
+
+	// MetricsTimeSeries is set when metrics are stored in a time-series collection
+	MetricsTimeSeries bool
 }
 
 // InitMongoDB initializes MongoDB connection with configuration
 // Update InitMongoDB for production
 func InitMongoDB(cfg *config.Config) (*MongoDB, error) {
+	db, err := ConnectMongoDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	database := db.Database
+
+	// Store metrics in a time-series collection when enabled, or when the
+	// collection was already migrated
+	timeSeries, err := ensureTimeSeriesMetrics(database, cfg.MetricsTimeSeries, cfg.GetMetricsRetentionDuration())
+	if err != nil {
+		log.Printf("Warning: Failed to set up time-series metrics: %v", err)
+	}
+	db.MetricsTimeSeries = timeSeries
+
+	// Create indexes
+	if err := createIndexes(database, timeSeries); err != nil {
+		log.Printf("Warning: Failed to create indexes: %v", err)
+	}
+
+	// Bring stored metric expiry in line with METRICS_RETENTION_DAYS
+	if err := reconcileRetention(database, cfg.GetMetricsRetentionDuration(), timeSeries); err != nil {
+		log.Printf("Warning: Failed to apply metrics retention: %v", err)
+	}
+
+	return db, nil
+}
+
+// ConnectMongoDB connects to MongoDB without preparing any collection, for
+// tools that manage the collections themselves
+func ConnectMongoDB(cfg *config.Config) (*MongoDB, error) {
 	clientOptions := options.Client().
 		ApplyURI(cfg.MongodbURI).
 		SetMaxPoolSize(100).
@@ -75,13 +111,6 @@ func InitMongoDB(cfg *config.Config) (*MongoDB, error) {
 		return nil, fmt.Errorf("failed to ping MongoDB after %d attempts: %v", maxRetries, err)
 	}
 
-	database := client.Database(cfg.DatabaseName)
-
-	// Create indexes
-	if err := createIndexes(database); err != nil {
-		log.Printf("Warning: Failed to create indexes: %v", err)
-	}
-
 	// Mask URI for production logging
 	maskedURI := cfg.MongodbURI
 	if cfg.IsProduction() {
@@ -91,19 +120,26 @@ func InitMongoDB(cfg *config.Config) (*MongoDB, error) {
 
 	return &MongoDB{
 		Client:   client,
-		Database: database,
+		Database: client.Database(cfg.DatabaseName),
 	}, nil
 }
 
 // createIndexes creates database indexes for optimal query performance
-func createIndexes(db *mongo.Database) error {
+func createIndexes(db *mongo.Database, timeSeries bool) error {
 	ctx := context.Background()
 
 	// Index for monitors collection
 	monitorsCollection := db.Collection("monitors")
+
+	// The URL alone used to be unique, which kept monitors of different
+	// types or DNS record types from checking the same host
+	if _, err := monitorsCollection.Indexes().DropOne(ctx, "url_1"); err != nil && !indexNotFound(err) {
+		return fmt.Errorf("failed to drop monitors url index: %v", err)
+	}
+
 	_, err := monitorsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{
-			Keys:    map[string]int{"url": 1},
+			Keys:    bson.D{{Key: "url", Value: 1}, {Key: "type", Value: 1}, {Key: "dns_record_type", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
 		{
@@ -117,30 +153,181 @@ func createIndexes(db *mongo.Database) error {
 		return fmt.Errorf("failed to create monitors indexes: %v", err)
 	}
 
-	// Index for metrics collection (time-series optimization)
+	// Index for metrics collection
 	metricsCollection := db.Collection("metrics")
-	_, err = metricsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+
+	// Earlier versions also indexed monitor_id and checked_at on their own,
+	// which the compound index covers, and built the compound key from a map
+	// whose field order was not fixed
+	if !timeSeries {
+		for _, name := range legacyMetricsIndexes {
+			if _, err := metricsCollection.Indexes().DropOne(ctx, name); err != nil && !indexNotFound(err) {
+				return fmt.Errorf("failed to drop metrics index %s: %v", name, err)
+			}
+		}
+	}
+
+	_, err = metricsCollection.Indexes().CreateMany(ctx, MetricsIndexes(timeSeries))
+	if err != nil {
+		return fmt.Errorf("failed to create metrics indexes: %v", err)
+	}
+
+	// Index for metric rollups, one document per monitor, resolution and bucket
+	rollupsCollection := db.Collection(MetricRollupsCollection)
+	_, err = rollupsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{
-			Keys: map[string]int{"monitor_id": 1, "checked_at": -1},
+			Keys:    bson.D{{Key: "monitor_id", Value: 1}, {Key: "resolution", Value: 1}, {Key: "bucket_start", Value: -1}},
+			Options: options.Index().SetUnique(true),
 		},
 		{
-			Keys: map[string]int{"checked_at": -1},
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
 		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create metric rollups indexes: %v", err)
+	}
+
+	// Index for incidents collection
+	incidentsCollection := db.Collection("incidents")
+	_, err = incidentsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{
-			Keys: map[string]int{"monitor_id": 1},
+			Keys: bson.D{{Key: "monitor_id", Value: 1}, {Key: "started_at", Value: -1}},
 		},
 		{
-			Keys:    map[string]int{"checked_at": 1},
-			Options: options.Index().SetExpireAfterSeconds(30 * 24 * 60 * 60), // 30 days retention
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "started_at", Value: -1}},
 		},
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create metrics indexes: %v", err)
+		return fmt.Errorf("failed to create incidents indexes: %v", err)
+	}
+
+	// Index for notification delivery log
+	deliveriesCollection := db.Collection("notification_deliveries")
+	_, err = deliveriesCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "channel_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(deliveryRetention.Seconds())),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification deliveries indexes: %v", err)
 	}
 
 	return nil
 }
 
+// retentionSettingID identifies the settings document recording the metrics
+// retention that stored expiry dates were computed with
+const retentionSettingID = "metrics_retention"
+
+// reconcileRetention drops the legacy fixed TTL index on checked_at and, when
+// the configured retention differs from the one last applied, recomputes the
+// expiry of every metric belonging to a monitor without its own retention. A
+// time-series collection has a single expiry, which is set to retention.
+func reconcileRetention(db *mongo.Database, retention time.Duration, timeSeries bool) error {
+	ctx := context.Background()
+	metrics := db.Collection(MetricsCollection)
+
+	if timeSeries {
+		collMod := bson.D{
+			{Key: "collMod", Value: MetricsCollection},
+			{Key: "expireAfterSeconds", Value: int64(retention.Seconds())},
+		}
+		return db.RunCommand(ctx, collMod).Err()
+	}
+
+	if err := dropLegacyTTLIndex(ctx, metrics); err != nil {
+		return err
+	}
+
+	settings := db.Collection(SettingsCollection)
+	var applied struct {
+		Seconds int64 `bson:"seconds"`
+	}
+	err := settings.FindOne(ctx, bson.M{"_id": retentionSettingID}).Decode(&applied)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return fmt.Errorf("failed to read metrics retention setting: %v", err)
+	}
+	if err == nil && applied.Seconds == int64(retention.Seconds()) {
+		return nil
+	}
+
+	// Monitors with an override keep their own expiry; it is still applied
+	// here so metrics written before expires_at existed get one
+	var overrides []models.Monitor
+	err = findAll(db.Collection(MonitorsCollection), bson.M{"retention_days": bson.M{"$gt": 0}}, &overrides)
+	if err != nil {
+		return fmt.Errorf("failed to list retention overrides: %v", err)
+	}
+	overrideIDs := bson.A{}
+	for _, monitor := range overrides {
+		overrideIDs = append(overrideIDs, monitor.ID)
+		if err := setExpiry(metrics, bson.M{"monitor_id": monitor.ID}, monitor.MetricsRetention(retention)); err != nil {
+			return fmt.Errorf("failed to update metric expiry: %v", err)
+		}
+	}
+	if err := setExpiry(metrics, bson.M{"monitor_id": bson.M{"$nin": overrideIDs}}, retention); err != nil {
+		return fmt.Errorf("failed to update metric expiry: %v", err)
+	}
+
+	_, err = settings.UpdateOne(ctx,
+		bson.M{"_id": retentionSettingID},
+		bson.M{"$set": bson.M{"seconds": int64(retention.Seconds()), "updated_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save metrics retention setting: %v", err)
+	}
+
+	log.Printf("Applied metrics retention of %s to stored metrics", retention)
+	return nil
+}
+
+// dropLegacyTTLIndex removes the 30 day TTL index on checked_at created by
+// earlier versions, which would otherwise expire metrics regardless of the
+// configured retention
+func dropLegacyTTLIndex(ctx context.Context, metrics *mongo.Collection) error {
+	cursor, err := metrics.Indexes().List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list metrics indexes: %v", err)
+	}
+	var indexes []bson.M
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return fmt.Errorf("failed to list metrics indexes: %v", err)
+	}
+
+	for _, index := range indexes {
+		if _, ttl := index["expireAfterSeconds"]; !ttl {
+			continue
+		}
+		key, ok := index["key"].(bson.M)
+		if !ok || len(key) != 1 || key["checked_at"] == nil {
+			continue
+		}
+		name, _ := index["name"].(string)
+		if _, err := metrics.Indexes().DropOne(ctx, name); err != nil {
+			return fmt.Errorf("failed to drop legacy metrics TTL index: %v", err)
+		}
+		log.Printf("Dropped legacy metrics TTL index %s", name)
+	}
+	return nil
+}
+
+// legacyMetricsIndexes are the metrics indexes that createIndexes no longer
+// creates on a regular collection
+var legacyMetricsIndexes = []string{"monitor_id_1", "checked_at_-1", "checked_at_-1_monitor_id_1"}
+
+// indexNotFound reports whether dropping an index failed only because the
+// index or its collection does not exist
+func indexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && (cmdErr.Code == 26 || cmdErr.Code == 27) // NamespaceNotFound, IndexNotFound
+}
+
 // Disconnect closes the MongoDB connection
 func (m *MongoDB) Disconnect(ctx context.Context) error {
 	if m.Client != nil {
@@ -156,8 +343,15 @@ func (m *MongoDB) GetCollection(name string) *mongo.Collection {
 
 // Collections constants
 const (
-	MonitorsCollection = "monitors"
-	MetricsCollection  = "metrics"
+	MonitorsCollection               = "monitors"
+	MetricsCollection                = "metrics"
+	MetricRollupsCollection          = "metric_rollups"
+	IncidentsCollection              = "incidents"
+	NotificationChannelsCollection   = "notification_channels"
+	NotificationDeliveriesCollection = "notification_deliveries"
+	EscalationPoliciesCollection     = "escalation_policies"
+	MaintenanceWindowsCollection     = "maintenance_windows"
+	SettingsCollection               = "settings"
 )
 
 // Health checks database connection
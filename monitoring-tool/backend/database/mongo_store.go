@@ -0,0 +1,731 @@
+// database/mongo_store.go
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"monitoring-tool/models"
+)
+
+// MongoStore keeps everything in MongoDB collections
+type MongoStore struct {
+	db                 *MongoDB
+	monitors           *mongoMonitorStore
+	metrics            *mongoMetricStore
+	rollups            *mongoRollupStore
+	incidents          *mongoIncidentStore
+	notifications      *mongoNotificationStore
+	escalationPolicies *mongoEscalationPolicyStore
+	maintenanceWindows *mongoMaintenanceWindowStore
+}
+
+// NewMongoStore creates a store backed by a MongoDB connection
+func NewMongoStore(db *MongoDB) *MongoStore {
+	return &MongoStore{
+		db:        db,
+		monitors:  &mongoMonitorStore{collection: db.GetCollection(MonitorsCollection)},
+		metrics:   &mongoMetricStore{collection: db.GetCollection(MetricsCollection), timeSeries: db.MetricsTimeSeries},
+		rollups:   &mongoRollupStore{collection: db.GetCollection(MetricRollupsCollection)},
+		incidents: &mongoIncidentStore{collection: db.GetCollection(IncidentsCollection)},
+		notifications: &mongoNotificationStore{
+			channels:   db.GetCollection(NotificationChannelsCollection),
+			deliveries: db.GetCollection(NotificationDeliveriesCollection),
+		},
+		escalationPolicies: &mongoEscalationPolicyStore{collection: db.GetCollection(EscalationPoliciesCollection)},
+		maintenanceWindows: &mongoMaintenanceWindowStore{collection: db.GetCollection(MaintenanceWindowsCollection)},
+	}
+}
+
+func (s *MongoStore) Monitors() MonitorStore                     { return s.monitors }
+func (s *MongoStore) Metrics() MetricStore                       { return s.metrics }
+func (s *MongoStore) Rollups() RollupStore                       { return s.rollups }
+func (s *MongoStore) Incidents() IncidentStore                   { return s.incidents }
+func (s *MongoStore) Notifications() NotificationStore           { return s.notifications }
+func (s *MongoStore) EscalationPolicies() EscalationPolicyStore  { return s.escalationPolicies }
+func (s *MongoStore) MaintenanceWindows() MaintenanceWindowStore { return s.maintenanceWindows }
+
+// Health checks database connection
+func (s *MongoStore) Health() error {
+	return s.db.Health()
+}
+
+// Close disconnects from MongoDB
+func (s *MongoStore) Close(ctx context.Context) error {
+	return s.db.Disconnect(ctx)
+}
+
+// findAll decodes every document matching filter into results
+func findAll(collection *mongo.Collection, filter interface{}, results interface{}, opts ...*options.FindOptions) error {
+	cursor, err := collection.Find(context.Background(), filter, opts...)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.Background())
+
+	return cursor.All(context.Background(), results)
+}
+
+// notFound translates the driver's missing document error
+func notFound(err error) error {
+	if err == mongo.ErrNoDocuments {
+		return ErrNotFound
+	}
+	return err
+}
+
+type mongoMonitorStore struct {
+	collection *mongo.Collection
+}
+
+func (s *mongoMonitorStore) Create(monitor *models.Monitor) error {
+	result, err := s.collection.InsertOne(context.Background(), monitor)
+	if err != nil {
+		return err
+	}
+
+	monitor.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoMonitorStore) List() ([]models.Monitor, error) {
+	var monitors []models.Monitor
+	if err := findAll(s.collection, bson.M{}, &monitors); err != nil {
+		return nil, err
+	}
+	return monitors, nil
+}
+
+func (s *mongoMonitorStore) Get(id primitive.ObjectID) (*models.Monitor, error) {
+	var monitor models.Monitor
+	if err := s.collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&monitor); err != nil {
+		return nil, notFound(err)
+	}
+	return &monitor, nil
+}
+
+func (s *mongoMonitorStore) TargetExists(target *models.Monitor, excludeID *primitive.ObjectID) (bool, error) {
+	filter := bson.M{"url": target.URL, "type": checkType(target.Type)}
+	if filter["type"] == "http" {
+		// Monitors stored before types existed have none
+		filter["type"] = bson.M{"$in": bson.A{"http", "", nil}}
+	}
+	if target.Type == "dns" {
+		filter["dns_record_type"] = target.DNSRecordType
+	}
+	if excludeID != nil {
+		filter["_id"] = bson.M{"$ne": *excludeID}
+	}
+
+	count, err := s.collection.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (s *mongoMonitorStore) UpdateSettings(monitor *models.Monitor) error {
+	result, err := s.collection.UpdateOne(context.Background(), bson.M{"_id": monitor.ID}, bson.M{"$set": monitorSettings(monitor)})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// monitorSettings returns the user-editable fields of a monitor for a $set update
+func monitorSettings(monitor *models.Monitor) bson.M {
+	return bson.M{
+		"name":                     monitor.Name,
+		"url":                      monitor.URL,
+		"tags":                     monitor.Tags,
+		"type":                     monitor.Type,
+		"method":                   monitor.Method,
+		"interval":                 monitor.Interval,
+		"timeout":                  monitor.Timeout,
+		"dns_record_type":          monitor.DNSRecordType,
+		"dns_resolver":             monitor.DNSResolver,
+		"dns_expected":             monitor.DNSExpected,
+		"tls_expiry_warning_days":  monitor.TLSExpiryWarningDays,
+		"headers":                  monitor.Headers,
+		"body":                     monitor.Body,
+		"content_type":             monitor.ContentType,
+		"auth":                     monitor.Auth,
+		"follow_redirects":         monitor.FollowRedirects,
+		"max_redirects":            monitor.MaxRedirects,
+		"expected_final_host":      monitor.ExpectedFinalHost,
+		"expected_status_codes":    monitor.ExpectedStatusCodes,
+		"retries":                  monitor.Retries,
+		"retry_delay":              monitor.RetryDelay,
+		"warning_threshold":        monitor.WarningThreshold,
+		"critical_threshold":       monitor.CriticalThreshold,
+		"retention_days":           monitor.RetentionDays,
+		"body_assertions":          monitor.BodyAssertions,
+		"json_assertions":          monitor.JSONAssertions,
+		"notification_channel_ids": monitor.NotificationChannelIDs,
+		"escalation_policy_id":     monitor.EscalationPolicyID,
+		"updated_at":               monitor.UpdatedAt,
+	}
+}
+
+func (s *mongoMonitorStore) Pause(id primitive.ObjectID, pausedBy, reason string, at time.Time) (*models.Monitor, error) {
+	return s.findAndUpdate(id, bson.M{
+		"$set": bson.M{
+			"status":       "paused",
+			"is_active":    false,
+			"paused_at":    at,
+			"paused_by":    pausedBy,
+			"pause_reason": reason,
+			"is_flapping":  false,
+			"updated_at":   at,
+		},
+	})
+}
+
+func (s *mongoMonitorStore) Resume(id primitive.ObjectID, at time.Time) (*models.Monitor, error) {
+	return s.findAndUpdate(id, bson.M{
+		"$set": bson.M{
+			"status":     "active",
+			"is_active":  true,
+			"updated_at": at,
+		},
+		"$unset": bson.M{
+			"paused_at":    "",
+			"paused_by":    "",
+			"pause_reason": "",
+		},
+	})
+}
+
+// findAndUpdate applies an update to a monitor and returns the updated document
+func (s *mongoMonitorStore) findAndUpdate(id primitive.ObjectID, update bson.M) (*models.Monitor, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var monitor models.Monitor
+	if err := s.collection.FindOneAndUpdate(context.Background(), bson.M{"_id": id}, update, opts).Decode(&monitor); err != nil {
+		return nil, notFound(err)
+	}
+	return &monitor, nil
+}
+
+func (s *mongoMonitorStore) Delete(id primitive.ObjectID) error {
+	result, err := s.collection.DeleteOne(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoMonitorStore) UpdateStatus(id primitive.ObjectID, status string, statusCode int, responseTime int64, checkedAt time.Time) (string, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"current_status":      status,
+			"current_status_code": statusCode,
+			"current_response":    responseTime,
+			"last_checked":        checkedAt,
+			"updated_at":          time.Now(),
+		},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.Before).
+		SetProjection(bson.M{"current_status": 1})
+
+	var previous models.Monitor
+	if err := s.collection.FindOneAndUpdate(context.Background(), bson.M{"_id": id}, update, opts).Decode(&previous); err != nil {
+		return "", notFound(err)
+	}
+	return previous.CurrentStatus, nil
+}
+
+func (s *mongoMonitorStore) UpdateCertificate(id primitive.ObjectID, info *models.TLSInfo) error {
+	_, err := s.collection.UpdateOne(context.Background(), bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"certificate": info},
+	})
+	return err
+}
+
+func (s *mongoMonitorStore) SetFlapping(id primitive.ObjectID, flapping bool) error {
+	_, err := s.collection.UpdateOne(context.Background(), bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"is_flapping": flapping},
+	})
+	return err
+}
+
+func (s *mongoMonitorStore) ClearFlapping() error {
+	_, err := s.collection.UpdateMany(context.Background(),
+		bson.M{"is_flapping": true},
+		bson.M{"$set": bson.M{"is_flapping": false}},
+	)
+	return err
+}
+
+func (s *mongoMonitorStore) DetachEscalationPolicy(policyID primitive.ObjectID) error {
+	return unsetEscalationPolicy(s.collection, policyID)
+}
+
+// unsetEscalationPolicy removes a deleted policy from every document using it
+func unsetEscalationPolicy(collection *mongo.Collection, policyID primitive.ObjectID) error {
+	_, err := collection.UpdateMany(context.Background(),
+		bson.M{"escalation_policy_id": policyID},
+		bson.M{"$unset": bson.M{"escalation_policy_id": ""}},
+	)
+	return err
+}
+
+type mongoMetricStore struct {
+	collection *mongo.Collection
+	timeSeries bool // expired by the collection instead of each metric's expires_at
+}
+
+func (s *mongoMetricStore) Insert(metric *models.Metric) error {
+	result, err := s.collection.InsertOne(context.Background(), metric)
+	if err != nil {
+		return err
+	}
+
+	metric.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoMetricStore) Since(monitorID primitive.ObjectID, since time.Time, limit int) ([]models.Metric, error) {
+	filter := bson.M{
+		"monitor_id": monitorID,
+		"checked_at": bson.M{"$gte": since},
+	}
+	opts := options.Find().SetSort(bson.M{"checked_at": -1}).SetLimit(int64(limit))
+
+	var metrics []models.Metric
+	if err := findAll(s.collection, filter, &metrics, opts); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+func (s *mongoMetricStore) Between(monitorID primitive.ObjectID, from, to time.Time) ([]models.Metric, error) {
+	filter := bson.M{
+		"monitor_id": monitorID,
+		"checked_at": bson.M{"$gte": from, "$lt": to},
+	}
+	opts := options.Find().SetSort(bson.M{"checked_at": 1})
+
+	var metrics []models.Metric
+	if err := findAll(s.collection, filter, &metrics, opts); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+func (s *mongoMetricStore) CountSince(monitorID primitive.ObjectID, since time.Time) (MetricCounts, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"monitor_id":     monitorID,
+			"checked_at":     bson.M{"$gte": since},
+			"in_maintenance": bson.M{"$ne": true},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"total": bson.M{"$sum": 1},
+			"down":  bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", "down"}}, 1, 0}}},
+		}}},
+	}
+
+	cursor, err := s.collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return MetricCounts{}, err
+	}
+	defer cursor.Close(context.Background())
+
+	var counts MetricCounts
+	if cursor.Next(context.Background()) {
+		if err := cursor.Decode(&counts); err != nil {
+			return MetricCounts{}, err
+		}
+	}
+	return counts, cursor.Err()
+}
+
+func (s *mongoMetricStore) PerMonitorRetention() bool {
+	return !s.timeSeries
+}
+
+func (s *mongoMetricStore) SetRetention(monitorID primitive.ObjectID, retention time.Duration) error {
+	if s.timeSeries {
+		return errTimeSeriesRetention
+	}
+	return setExpiry(s.collection, bson.M{"monitor_id": monitorID}, retention)
+}
+
+// setExpiry recomputes expires_at from checked_at for the matching metrics
+func setExpiry(collection *mongo.Collection, filter bson.M, retention time.Duration) error {
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"expires_at": bson.M{"$add": bson.A{"$checked_at", retention.Milliseconds()}}}}},
+	}
+	_, err := collection.UpdateMany(context.Background(), filter, update)
+	return err
+}
+
+type mongoRollupStore struct {
+	collection *mongo.Collection
+}
+
+func (s *mongoRollupStore) Save(rollups []models.MetricRollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(rollups))
+	for _, rollup := range rollups {
+		filter := bson.M{
+			"monitor_id":   rollup.MonitorID,
+			"resolution":   rollup.Resolution,
+			"bucket_start": rollup.BucketStart,
+		}
+		writes = append(writes, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(rollup).SetUpsert(true))
+	}
+
+	_, err := s.collection.BulkWrite(context.Background(), writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (s *mongoRollupStore) Since(monitorID primitive.ObjectID, resolution string, since time.Time) ([]models.MetricRollup, error) {
+	filter := bson.M{
+		"monitor_id":   monitorID,
+		"resolution":   resolution,
+		"bucket_start": bson.M{"$gte": since},
+	}
+	opts := options.Find().SetSort(bson.M{"bucket_start": -1})
+
+	var rollups []models.MetricRollup
+	if err := findAll(s.collection, filter, &rollups, opts); err != nil {
+		return nil, err
+	}
+	return rollups, nil
+}
+
+func (s *mongoRollupStore) Latest(monitorID primitive.ObjectID, resolution string) (*models.MetricRollup, error) {
+	filter := bson.M{"monitor_id": monitorID, "resolution": resolution}
+	opts := options.FindOne().SetSort(bson.M{"bucket_start": -1})
+
+	var rollup models.MetricRollup
+	if err := s.collection.FindOne(context.Background(), filter, opts).Decode(&rollup); err != nil {
+		return nil, notFound(err)
+	}
+	return &rollup, nil
+}
+
+type mongoIncidentStore struct {
+	collection *mongo.Collection
+}
+
+func (s *mongoIncidentStore) Insert(incident *models.Incident) error {
+	result, err := s.collection.InsertOne(context.Background(), incident)
+	if err != nil {
+		return err
+	}
+
+	incident.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoIncidentStore) Get(id primitive.ObjectID) (*models.Incident, error) {
+	var incident models.Incident
+	if err := s.collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&incident); err != nil {
+		return nil, notFound(err)
+	}
+	return &incident, nil
+}
+
+func (s *mongoIncidentStore) FindOpen(monitorID primitive.ObjectID) (*models.Incident, error) {
+	var incident models.Incident
+	if err := s.collection.FindOne(context.Background(), bson.M{"monitor_id": monitorID, "status": "open"}).Decode(&incident); err != nil {
+		return nil, notFound(err)
+	}
+	return &incident, nil
+}
+
+func (s *mongoIncidentStore) RecordFailure(monitorID primitive.ObjectID, lastError string) error {
+	filter := bson.M{"monitor_id": monitorID, "status": "open"}
+	update := bson.M{
+		"$inc": bson.M{"affected_checks": 1},
+		"$set": bson.M{"last_error": lastError},
+	}
+	return notFound(s.collection.FindOneAndUpdate(context.Background(), filter, update).Err())
+}
+
+func (s *mongoIncidentStore) Resolve(id primitive.ObjectID, resolvedAt time.Time, duration int64) error {
+	update := bson.M{
+		"$set": bson.M{
+			"status":      "resolved",
+			"resolved_at": resolvedAt,
+			"duration":    duration,
+		},
+	}
+	_, err := s.collection.UpdateOne(context.Background(), bson.M{"_id": id}, update)
+	return err
+}
+
+func (s *mongoIncidentStore) Acknowledge(id primitive.ObjectID, acknowledgedBy string, at time.Time) (*models.Incident, error) {
+	filter := bson.M{"_id": id, "status": "open", "acknowledged_at": nil}
+	update := bson.M{
+		"$set": bson.M{
+			"acknowledged_at": at,
+			"acknowledged_by": acknowledgedBy,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var incident models.Incident
+	if err := s.collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&incident); err != nil {
+		return nil, notFound(err)
+	}
+	return &incident, nil
+}
+
+func (s *mongoIncidentStore) AdvanceEscalation(id primitive.ObjectID, from, to int) (bool, error) {
+	filter := bson.M{
+		"_id":              id,
+		"status":           "open",
+		"acknowledged_at":  nil,
+		"escalation_level": from,
+	}
+	result, err := s.collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"escalation_level": to}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (s *mongoIncidentStore) HoldEscalation(id primitive.ObjectID, at time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":                id,
+		"status":             "open",
+		"acknowledged_at":    nil,
+		"escalation_held_at": nil,
+	}
+	result, err := s.collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"escalation_held_at": at}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (s *mongoIncidentStore) ResumeEscalation(id primitive.ObjectID, startedAt time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":                id,
+		"status":             "open",
+		"acknowledged_at":    nil,
+		"escalation_held_at": bson.M{"$ne": nil},
+	}
+	update := bson.M{
+		"$set":   bson.M{"escalation_started_at": startedAt},
+		"$unset": bson.M{"escalation_held_at": ""},
+	}
+	result, err := s.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (s *mongoIncidentStore) DetachEscalationPolicy(policyID primitive.ObjectID) error {
+	return unsetEscalationPolicy(s.collection, policyID)
+}
+
+func (s *mongoIncidentStore) List(filter IncidentFilter, limit int) ([]models.Incident, error) {
+	query := bson.M{}
+	if filter.MonitorID != nil {
+		query["monitor_id"] = *filter.MonitorID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Escalating {
+		query["status"] = "open"
+		query["acknowledged_at"] = nil
+		query["escalation_policy_id"] = bson.M{"$exists": true}
+	}
+	opts := options.Find().SetSort(bson.M{"started_at": -1}).SetLimit(int64(limit))
+
+	incidents := []models.Incident{}
+	if err := findAll(s.collection, query, &incidents, opts); err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+type mongoNotificationStore struct {
+	channels   *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+func (s *mongoNotificationStore) CreateChannel(channel *models.NotificationChannel) error {
+	result, err := s.channels.InsertOne(context.Background(), channel)
+	if err != nil {
+		return err
+	}
+
+	channel.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoNotificationStore) ListChannels() ([]models.NotificationChannel, error) {
+	channels := []models.NotificationChannel{}
+	if err := findAll(s.channels, bson.M{}, &channels); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+func (s *mongoNotificationStore) GetChannel(id primitive.ObjectID) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	if err := s.channels.FindOne(context.Background(), bson.M{"_id": id}).Decode(&channel); err != nil {
+		return nil, notFound(err)
+	}
+	return &channel, nil
+}
+
+func (s *mongoNotificationStore) DeleteChannel(id primitive.ObjectID) error {
+	result, err := s.channels.DeleteOne(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoNotificationStore) CountChannels(ids []primitive.ObjectID) (int, error) {
+	count, err := s.channels.CountDocuments(context.Background(), bson.M{"_id": bson.M{"$in": ids}})
+	return int(count), err
+}
+
+func (s *mongoNotificationStore) InsertDelivery(delivery *models.NotificationDelivery) error {
+	result, err := s.deliveries.InsertOne(context.Background(), delivery)
+	if err != nil {
+		return err
+	}
+
+	delivery.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoNotificationStore) ListDeliveries(channelID *primitive.ObjectID, limit int) ([]models.NotificationDelivery, error) {
+	filter := bson.M{}
+	if channelID != nil {
+		filter["channel_id"] = *channelID
+	}
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(int64(limit))
+
+	deliveries := []models.NotificationDelivery{}
+	if err := findAll(s.deliveries, filter, &deliveries, opts); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+type mongoEscalationPolicyStore struct {
+	collection *mongo.Collection
+}
+
+func (s *mongoEscalationPolicyStore) Create(policy *models.EscalationPolicy) error {
+	result, err := s.collection.InsertOne(context.Background(), policy)
+	if err != nil {
+		return err
+	}
+
+	policy.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoEscalationPolicyStore) List() ([]models.EscalationPolicy, error) {
+	policies := []models.EscalationPolicy{}
+	if err := findAll(s.collection, bson.M{}, &policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (s *mongoEscalationPolicyStore) Get(id primitive.ObjectID) (*models.EscalationPolicy, error) {
+	var policy models.EscalationPolicy
+	if err := s.collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&policy); err != nil {
+		return nil, notFound(err)
+	}
+	return &policy, nil
+}
+
+func (s *mongoEscalationPolicyStore) Delete(id primitive.ObjectID) error {
+	result, err := s.collection.DeleteOne(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type mongoMaintenanceWindowStore struct {
+	collection *mongo.Collection
+}
+
+func (s *mongoMaintenanceWindowStore) Create(window *models.MaintenanceWindow) error {
+	result, err := s.collection.InsertOne(context.Background(), window)
+	if err != nil {
+		return err
+	}
+
+	window.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *mongoMaintenanceWindowStore) List() ([]models.MaintenanceWindow, error) {
+	windows := []models.MaintenanceWindow{}
+	if err := findAll(s.collection, bson.M{}, &windows); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+func (s *mongoMaintenanceWindowStore) ListForMonitor(monitor models.Monitor, at time.Time) ([]models.MaintenanceWindow, error) {
+	filter := bson.M{
+		"$and": []bson.M{
+			{"$or": []bson.M{
+				{"monitor_ids": monitor.ID},
+				{"tags": bson.M{"$in": append([]string{}, monitor.Tags...)}},
+			}},
+			{"$or": []bson.M{
+				{"schedule": bson.M{"$exists": true}},
+				{"starts_at": bson.M{"$lte": at}, "ends_at": bson.M{"$gt": at}},
+			}},
+		},
+	}
+
+	var windows []models.MaintenanceWindow
+	if err := findAll(s.collection, filter, &windows); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+func (s *mongoMaintenanceWindowStore) Delete(id primitive.ObjectID) error {
+	result, err := s.collection.DeleteOne(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+// database/store.go
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/config"
+	"monitoring-tool/models"
+)
+
+// ErrNotFound is returned by stores when the requested document does not exist
+var ErrNotFound = errors.New("not found")
+
+// deliveryRetention is how long the notification delivery log is kept
+const deliveryRetention = 30 * 24 * time.Hour
+
+// Store gives the services access to every kind of persisted data
+type Store interface {
+	Monitors() MonitorStore
+	Metrics() MetricStore
+	Rollups() RollupStore
+	Incidents() IncidentStore
+	Notifications() NotificationStore
+	EscalationPolicies() EscalationPolicyStore
+	MaintenanceWindows() MaintenanceWindowStore
+
+	Health() error
+	Close(ctx context.Context) error
+}
+
+// MonitorStore persists monitors and their current status
+type MonitorStore interface {
+	Create(monitor *models.Monitor) error
+	List() ([]models.Monitor, error)
+	Get(id primitive.ObjectID) (*models.Monitor, error)
+	// TargetExists reports whether a monitor other than excludeID checks the
+	// same target, as defined by sameTarget
+	TargetExists(monitor *models.Monitor, excludeID *primitive.ObjectID) (bool, error)
+	// UpdateSettings saves the user-editable fields of the monitor
+	UpdateSettings(monitor *models.Monitor) error
+	Pause(id primitive.ObjectID, pausedBy, reason string, at time.Time) (*models.Monitor, error)
+	Resume(id primitive.ObjectID, at time.Time) (*models.Monitor, error)
+	Delete(id primitive.ObjectID) error
+
+	// UpdateStatus records the result of the latest check and returns the
+	// status the monitor had before
+	UpdateStatus(id primitive.ObjectID, status string, statusCode int, responseTime int64, checkedAt time.Time) (string, error)
+	UpdateCertificate(id primitive.ObjectID, info *models.TLSInfo) error
+	SetFlapping(id primitive.ObjectID, flapping bool) error
+	ClearFlapping() error
+	DetachEscalationPolicy(policyID primitive.ObjectID) error
+}
+
+// MetricStore persists check results
+type MetricStore interface {
+	Insert(metric *models.Metric) error
+	// Since returns a monitor's metrics checked at or after since, newest
+	// first. A limit of 0 returns every match.
+	Since(monitorID primitive.ObjectID, since time.Time, limit int) ([]models.Metric, error)
+	// Between returns a monitor's metrics checked at or after from and
+	// before to, oldest first
+	Between(monitorID primitive.ObjectID, from, to time.Time) ([]models.Metric, error)
+	// CountSince counts a monitor's metrics checked at or after since
+	CountSince(monitorID primitive.ObjectID, since time.Time) (MetricCounts, error)
+	// SetRetention applies a monitor's new retention to the metrics it
+	// already has
+	SetRetention(monitorID primitive.ObjectID, retention time.Duration) error
+	// PerMonitorRetention reports whether monitors can keep their metrics
+	// for their own retention
+	PerMonitorRetention() bool
+}
+
+// MetricCounts counts the checks of a monitor that are not in a maintenance
+// window, which are the ones uptime is computed from
+type MetricCounts struct {
+	Total int `bson:"total"`
+	Down  int `bson:"down"`
+}
+
+// add counts a check, leaving out those made during maintenance
+func (c *MetricCounts) add(status string, inMaintenance bool) {
+	if inMaintenance {
+		return
+	}
+	c.Total++
+	if status == "down" {
+		c.Down++
+	}
+}
+
+// RollupStore persists metrics aggregated into time buckets
+type RollupStore interface {
+	// Save stores rollups, replacing any with the same monitor, resolution
+	// and bucket start
+	Save(rollups []models.MetricRollup) error
+	// Since returns a monitor's rollups of a resolution whose bucket starts
+	// at or after since, newest first
+	Since(monitorID primitive.ObjectID, resolution string, since time.Time) ([]models.MetricRollup, error)
+	// Latest returns a monitor's most recent rollup of a resolution
+	Latest(monitorID primitive.ObjectID, resolution string) (*models.MetricRollup, error)
+}
+
+// IncidentFilter selects incidents to list. Zero fields match everything.
+type IncidentFilter struct {
+	MonitorID  *primitive.ObjectID
+	Status     string
+	Escalating bool // open, unacknowledged incidents following an escalation policy
+}
+
+// matches reports whether an incident is selected by the filter
+func (f IncidentFilter) matches(incident models.Incident) bool {
+	if f.MonitorID != nil && incident.MonitorID != *f.MonitorID {
+		return false
+	}
+	if f.Status != "" && incident.Status != f.Status {
+		return false
+	}
+	if f.Escalating && (incident.Status != "open" || incident.AcknowledgedAt != nil || incident.EscalationPolicyID == nil) {
+		return false
+	}
+	return true
+}
+
+// IncidentStore persists incidents
+type IncidentStore interface {
+	Insert(incident *models.Incident) error
+	Get(id primitive.ObjectID) (*models.Incident, error)
+	FindOpen(monitorID primitive.ObjectID) (*models.Incident, error)
+	// RecordFailure counts a failed check towards the monitor's open incident
+	// and returns ErrNotFound when there is none
+	RecordFailure(monitorID primitive.ObjectID, lastError string) error
+	Resolve(id primitive.ObjectID, resolvedAt time.Time, duration int64) error
+	// Acknowledge marks an open, unacknowledged incident and returns it, or
+	// ErrNotFound when no such incident exists
+	Acknowledge(id primitive.ObjectID, acknowledgedBy string, at time.Time) (*models.Incident, error)
+	// AdvanceEscalation moves an open, unacknowledged incident from one
+	// escalation level to another and reports whether it did
+	AdvanceEscalation(id primitive.ObjectID, from, to int) (bool, error)
+	// HoldEscalation marks the escalation of an open, unacknowledged
+	// incident as held since at and reports whether it was not held already
+	HoldEscalation(id primitive.ObjectID, at time.Time) (bool, error)
+	// ResumeEscalation ends the hold of an open, unacknowledged incident,
+	// counting step delays from startedAt, and reports whether it was held
+	ResumeEscalation(id primitive.ObjectID, startedAt time.Time) (bool, error)
+	DetachEscalationPolicy(policyID primitive.ObjectID) error
+	// List returns matching incidents, newest first. A limit of 0 returns
+	// every match.
+	List(filter IncidentFilter, limit int) ([]models.Incident, error)
+}
+
+// NotificationStore persists notification channels and the delivery log
+type NotificationStore interface {
+	CreateChannel(channel *models.NotificationChannel) error
+	ListChannels() ([]models.NotificationChannel, error)
+	GetChannel(id primitive.ObjectID) (*models.NotificationChannel, error)
+	DeleteChannel(id primitive.ObjectID) error
+	// CountChannels returns how many of the IDs refer to existing channels
+	CountChannels(ids []primitive.ObjectID) (int, error)
+
+	InsertDelivery(delivery *models.NotificationDelivery) error
+	// ListDeliveries returns deliveries newest first, optionally for one channel
+	ListDeliveries(channelID *primitive.ObjectID, limit int) ([]models.NotificationDelivery, error)
+}
+
+// EscalationPolicyStore persists escalation policies
+type EscalationPolicyStore interface {
+	Create(policy *models.EscalationPolicy) error
+	List() ([]models.EscalationPolicy, error)
+	Get(id primitive.ObjectID) (*models.EscalationPolicy, error)
+	Delete(id primitive.ObjectID) error
+}
+
+// MaintenanceWindowStore persists maintenance windows
+type MaintenanceWindowStore interface {
+	Create(window *models.MaintenanceWindow) error
+	List() ([]models.MaintenanceWindow, error)
+	// ListForMonitor returns the windows that may cover the monitor at the
+	// given time. Callers still check each window's schedule.
+	ListForMonitor(monitor models.Monitor, at time.Time) ([]models.MaintenanceWindow, error)
+	Delete(id primitive.ObjectID) error
+}
+
+// Open connects to the storage backend selected by STORAGE_DRIVER
+func Open(cfg *config.Config) (Store, error) {
+	switch cfg.StorageDriver {
+	case "mongo":
+		db, err := InitMongoDB(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewMongoStore(db), nil
+	case "memory":
+		return NewMemoryStore(cfg.GetMetricsRetentionDuration()), nil
+	case "bolt":
+		return OpenBoltStore(cfg.StoragePath, cfg.GetMetricsRetentionDuration())
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", cfg.StorageDriver)
+	}
+}
+
+// The helpers below are shared by the stores that update documents in Go
+// rather than in the database server.
+
+// sameTarget reports whether two monitors check the same thing: the same URL
+// with the same monitor type and, for DNS monitors, the same record type
+func sameTarget(a, b *models.Monitor) bool {
+	if a.URL != b.URL || checkType(a.Type) != checkType(b.Type) {
+		return false
+	}
+	return a.Type != "dns" || a.DNSRecordType == b.DNSRecordType
+}
+
+// checkType returns a monitor's type, treating monitors stored before
+// types existed as http monitors
+func checkType(monitorType string) string {
+	if monitorType == "" {
+		return "http"
+	}
+	return monitorType
+}
+
+// applySettings copies the user-editable fields of a monitor, the same ones
+// the MongoDB store sets, leaving its status alone
+func applySettings(stored *models.Monitor, settings models.Monitor) {
+	stored.Name = settings.Name
+	stored.URL = settings.URL
+	stored.Tags = settings.Tags
+	stored.Type = settings.Type
+	stored.Method = settings.Method
+	stored.Interval = settings.Interval
+	stored.Timeout = settings.Timeout
+	stored.DNSRecordType = settings.DNSRecordType
+	stored.DNSResolver = settings.DNSResolver
+	stored.DNSExpected = settings.DNSExpected
+	stored.TLSExpiryWarningDays = settings.TLSExpiryWarningDays
+	stored.Headers = settings.Headers
+	stored.Body = settings.Body
+	stored.ContentType = settings.ContentType
+	stored.Auth = settings.Auth
+	stored.FollowRedirects = settings.FollowRedirects
+	stored.MaxRedirects = settings.MaxRedirects
+	stored.ExpectedFinalHost = settings.ExpectedFinalHost
+	stored.ExpectedStatusCodes = settings.ExpectedStatusCodes
+	stored.Retries = settings.Retries
+	stored.RetryDelay = settings.RetryDelay
+	stored.WarningThreshold = settings.WarningThreshold
+	stored.CriticalThreshold = settings.CriticalThreshold
+	stored.RetentionDays = settings.RetentionDays
+	stored.BodyAssertions = settings.BodyAssertions
+	stored.JSONAssertions = settings.JSONAssertions
+	stored.NotificationChannelIDs = settings.NotificationChannelIDs
+	stored.EscalationPolicyID = settings.EscalationPolicyID
+	stored.UpdatedAt = settings.UpdatedAt
+}
+
+// pause marks a monitor as paused
+func pause(monitor *models.Monitor, pausedBy, reason string, at time.Time) {
+	monitor.Status = "paused"
+	monitor.IsActive = false
+	monitor.PausedAt = &at
+	monitor.PausedBy = pausedBy
+	monitor.PauseReason = reason
+	monitor.IsFlapping = false
+	monitor.UpdatedAt = at
+}
+
+// resume marks a paused monitor as active again
+func resume(monitor *models.Monitor, at time.Time) {
+	monitor.Status = "active"
+	monitor.IsActive = true
+	monitor.PausedAt = nil
+	monitor.PausedBy = ""
+	monitor.PauseReason = ""
+	monitor.UpdatedAt = at
+}
+
+// sortNewestFirst orders incidents by start time, most recent first
+func sortNewestFirst(incidents []models.Incident) {
+	sort.SliceStable(incidents, func(i, j int) bool {
+		return incidents[i].StartedAt.After(incidents[j].StartedAt)
+	})
+}
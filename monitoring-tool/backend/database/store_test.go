@@ -0,0 +1,107 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/models"
+)
+
+func TestMetricCountSince(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store { return NewMemoryStore(24 * time.Hour) },
+		"bolt": func(t *testing.T) Store {
+			return openTestBoltStore(t, filepath.Join(t.TempDir(), "monitor.db"))
+		},
+	}
+
+	now := time.Now().Truncate(time.Millisecond) // stored times keep milliseconds, as in BSON
+	since := now.Add(-time.Hour)
+	metrics := []models.Metric{
+		{Status: "down", CheckedAt: now.Add(-2 * time.Hour)}, // before since
+		{Status: "up", CheckedAt: since},
+		{Status: "up", CheckedAt: now.Add(-30 * time.Minute)},
+		{Status: "degraded", CheckedAt: now.Add(-20 * time.Minute)},
+		{Status: "down", CheckedAt: now.Add(-10 * time.Minute)},
+		{Status: "down", CheckedAt: now.Add(-5 * time.Minute), InMaintenance: true},
+		{Status: "up", CheckedAt: now.Add(-time.Minute), InMaintenance: true},
+	}
+
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := open(t)
+			monitorID := primitive.NewObjectID()
+			for _, metric := range metrics {
+				metric.MonitorID = monitorID
+				if err := store.Metrics().Insert(&metric); err != nil {
+					t.Fatalf("failed to insert metric: %v", err)
+				}
+			}
+
+			counts, err := store.Metrics().CountSince(monitorID, since)
+			if err != nil {
+				t.Fatalf("CountSince failed: %v", err)
+			}
+			if want := (MetricCounts{Total: 4, Down: 1}); counts != want {
+				t.Errorf("CountSince = %+v, want %+v", counts, want)
+			}
+
+			if counts, err := store.Metrics().CountSince(primitive.NewObjectID(), since); err != nil || counts != (MetricCounts{}) {
+				t.Errorf("CountSince of a monitor without metrics = %+v, %v, want zero", counts, err)
+			}
+		})
+	}
+}
+
+func TestIncidentEscalationHold(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store { return NewMemoryStore(24 * time.Hour) },
+		"bolt": func(t *testing.T) Store {
+			return openTestBoltStore(t, filepath.Join(t.TempDir(), "monitor.db"))
+		},
+	}
+
+	heldAt := time.Now().Truncate(time.Millisecond)
+	startedAt := heldAt.Add(time.Hour)
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			incidents := open(t).Incidents()
+			incident := &models.Incident{MonitorID: primitive.NewObjectID(), Status: "open", StartedAt: heldAt}
+			if err := incidents.Insert(incident); err != nil {
+				t.Fatalf("failed to insert incident: %v", err)
+			}
+
+			if resumed, err := incidents.ResumeEscalation(incident.ID, startedAt); err != nil || resumed {
+				t.Fatalf("ResumeEscalation without a hold = %v, %v, want false", resumed, err)
+			}
+			if held, err := incidents.HoldEscalation(incident.ID, heldAt); err != nil || !held {
+				t.Fatalf("HoldEscalation = %v, %v, want true", held, err)
+			}
+			if held, err := incidents.HoldEscalation(incident.ID, startedAt); err != nil || held {
+				t.Fatalf("second HoldEscalation = %v, %v, want false", held, err)
+			}
+			if stored, err := incidents.Get(incident.ID); err != nil || stored.EscalationHeldAt == nil || !stored.EscalationHeldAt.Equal(heldAt) {
+				t.Fatalf("held incident = %+v, %v, want held at %v", stored, err, heldAt)
+			}
+
+			if resumed, err := incidents.ResumeEscalation(incident.ID, startedAt); err != nil || !resumed {
+				t.Fatalf("ResumeEscalation = %v, %v, want true", resumed, err)
+			}
+			stored, err := incidents.Get(incident.ID)
+			if err != nil || stored.EscalationHeldAt != nil || stored.EscalationStartedAt == nil || !stored.EscalationStartedAt.Equal(startedAt) {
+				t.Fatalf("resumed incident = %+v, %v, want escalation started at %v", stored, err, startedAt)
+			}
+
+			// Acknowledged incidents no longer escalate, so they are not held
+			if _, err := incidents.Acknowledge(incident.ID, "alice", startedAt); err != nil {
+				t.Fatalf("Acknowledge failed: %v", err)
+			}
+			if held, err := incidents.HoldEscalation(incident.ID, startedAt); err != nil || held {
+				t.Fatalf("HoldEscalation after acknowledgement = %v, %v, want false", held, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// database/timeseries.go
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LegacyMetricsCollection holds the regular metrics collection while it is
+// copied into a time-series collection
+const LegacyMetricsCollection = "metrics_legacy"
+
+// timeSeriesMigrationID identifies the settings document recording a
+// completed time-series migration
+const timeSeriesMigrationID = "metrics_timeseries_migration"
+
+// errTimeSeriesRetention is returned when a monitor's retention is changed
+// while metrics are stored in a time-series collection, which expires every
+// metric after the same time
+var errTimeSeriesRetention = errors.New("per-monitor retention is not supported when metrics are a time-series collection")
+
+// CollectionType returns "timeseries" or "collection" for an existing
+// collection, and "" when it does not exist
+func CollectionType(ctx context.Context, db *mongo.Database, name string) (string, error) {
+	specs, err := db.ListCollectionSpecifications(ctx, bson.M{"name": name})
+	if err != nil {
+		return "", err
+	}
+	if len(specs) == 0 {
+		return "", nil
+	}
+	return specs[0].Type, nil
+}
+
+// CreateTimeSeriesCollection creates a time-series collection for metrics,
+// bucketed by monitor and expiring documents after retention
+func CreateTimeSeriesCollection(ctx context.Context, db *mongo.Database, name string, retention time.Duration) error {
+	timeSeries := options.TimeSeries().
+		SetTimeField("checked_at").
+		SetMetaField("monitor_id").
+		SetGranularity("seconds")
+	opts := options.CreateCollection().
+		SetTimeSeriesOptions(timeSeries).
+		SetExpireAfterSeconds(int64(retention.Seconds()))
+	return db.CreateCollection(ctx, name, opts)
+}
+
+// MetricsIndexes returns the indexes of the metrics collection. A
+// time-series collection already stores documents by monitor and check
+// time, and expires them itself, so it only needs the range query index.
+func MetricsIndexes(timeSeries bool) []mongo.IndexModel {
+	if timeSeries {
+		return []mongo.IndexModel{
+			{
+				Keys: bson.D{{Key: "monitor_id", Value: 1}, {Key: "checked_at", Value: -1}},
+			},
+		}
+	}
+
+	// The compound index also serves queries by monitor alone, so it needs
+	// an ordered key with monitor_id first
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "monitor_id", Value: 1}, {Key: "checked_at", Value: -1}},
+		},
+		{
+			// Each metric carries its own expiry so monitors can override the retention
+			Keys:    map[string]int{"expires_at": 1},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+}
+
+// ensureTimeSeriesMetrics creates the metrics collection as a time-series
+// collection when it does not exist yet and reports whether metrics are
+// stored in one
+func ensureTimeSeriesMetrics(db *mongo.Database, create bool, retention time.Duration) (bool, error) {
+	ctx := context.Background()
+	kind, err := CollectionType(ctx, db, MetricsCollection)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case kind == "timeseries":
+		return true, nil
+	case kind == "" && create:
+		if err := CreateTimeSeriesCollection(ctx, db, MetricsCollection, retention); err != nil {
+			return false, fmt.Errorf("failed to create time-series metrics collection: %v", err)
+		}
+		log.Printf("Created time-series metrics collection")
+		return true, nil
+	case kind != "" && create:
+		log.Printf("Warning: METRICS_TIMESERIES is set but %s is a regular collection; stop the server and run the migrate-metrics command to convert it", MetricsCollection)
+	}
+	return false, nil
+}
+
+// MigrateMetricsToTimeSeries copies the regular metrics collection into a
+// new time-series collection and returns the number of metrics copied.
+// Metrics older than retention are skipped since they would expire at once.
+// The regular collection is renamed to LegacyMetricsCollection first and
+// dropped once the copy is complete unless keepLegacy is set. An interrupted
+// migration is restarted from the legacy collection when run again. The
+// server must not be running, as metrics it writes meanwhile would be lost.
+func MigrateMetricsToTimeSeries(db *mongo.Database, retention time.Duration, batchSize int, keepLegacy bool) (int64, error) {
+	ctx := context.Background()
+	settings := db.Collection(SettingsCollection)
+
+	err := settings.FindOne(ctx, bson.M{"_id": timeSeriesMigrationID}).Err()
+	if err == nil {
+		return 0, fmt.Errorf("metrics were already migrated to a time-series collection")
+	}
+	if err != mongo.ErrNoDocuments {
+		return 0, err
+	}
+
+	current, err := CollectionType(ctx, db, MetricsCollection)
+	if err != nil {
+		return 0, err
+	}
+	legacy, err := CollectionType(ctx, db, LegacyMetricsCollection)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case current == "collection" && legacy != "":
+		return 0, fmt.Errorf("both %s and %s exist; remove %s to migrate again", MetricsCollection, LegacyMetricsCollection, LegacyMetricsCollection)
+	case current == "collection":
+		log.Printf("Renaming %s to %s", MetricsCollection, LegacyMetricsCollection)
+		rename := bson.D{
+			{Key: "renameCollection", Value: db.Name() + "." + MetricsCollection},
+			{Key: "to", Value: db.Name() + "." + LegacyMetricsCollection},
+		}
+		if err := db.Client().Database("admin").RunCommand(ctx, rename).Err(); err != nil {
+			return 0, fmt.Errorf("failed to rename %s: %v", MetricsCollection, err)
+		}
+	case current == "timeseries" && legacy != "":
+		// A previous run stopped part way through the copy
+		log.Printf("Restarting interrupted migration")
+		if err := db.Collection(MetricsCollection).Drop(ctx); err != nil {
+			return 0, err
+		}
+	case current == "timeseries":
+		return 0, fmt.Errorf("%s is already a time-series collection", MetricsCollection)
+	}
+
+	if err := CreateTimeSeriesCollection(ctx, db, MetricsCollection, retention); err != nil {
+		return 0, fmt.Errorf("failed to create time-series metrics collection: %v", err)
+	}
+	metrics := db.Collection(MetricsCollection)
+	if _, err := metrics.Indexes().CreateMany(ctx, MetricsIndexes(true)); err != nil {
+		return 0, fmt.Errorf("failed to create metrics indexes: %v", err)
+	}
+
+	var copied int64
+	if legacy != "" || current == "collection" {
+		copied, err = copyMetrics(ctx, db.Collection(LegacyMetricsCollection), metrics, time.Now().Add(-retention), batchSize)
+		if err != nil {
+			return copied, err
+		}
+	}
+
+	_, err = settings.UpdateOne(ctx,
+		bson.M{"_id": timeSeriesMigrationID},
+		bson.M{"$set": bson.M{"completed_at": time.Now(), "copied": copied}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return copied, fmt.Errorf("failed to record migration: %v", err)
+	}
+
+	if !keepLegacy {
+		if err := db.Collection(LegacyMetricsCollection).Drop(ctx); err != nil {
+			return copied, fmt.Errorf("failed to drop %s: %v", LegacyMetricsCollection, err)
+		}
+	}
+	return copied, nil
+}
+
+// copyMetrics inserts the metrics of from checked at or after since into
+// to, batchSize documents at a time
+func copyMetrics(ctx context.Context, from, to *mongo.Collection, since time.Time, batchSize int) (int64, error) {
+	filter := bson.M{"checked_at": bson.M{"$gte": since}}
+	total, err := from.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	cursor, err := from.Find(ctx, filter, options.Find().SetBatchSize(int32(batchSize)))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var copied int64
+	batch := make([]interface{}, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if _, err := to.InsertMany(ctx, batch, options.InsertMany().SetOrdered(false)); err != nil {
+			return err
+		}
+		copied += int64(len(batch))
+		batch = batch[:0]
+		log.Printf("Copied %d/%d metrics", copied, total)
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		// Copy the raw document so no field is lost in decoding
+		batch = append(batch, append(bson.Raw(nil), cursor.Current...))
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return copied, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return copied, err
+	}
+	if err := flush(); err != nil {
+		return copied, err
+	}
+	return copied, nil
+}
@@ -2,8 +2,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,13 +16,15 @@ import (
 
 type APIHandler struct {
 	monitorService *services.MonitorService
+	rollupService  *services.RollupService
 	wsHub          *services.WebSocketHub
 }
 
 // NewAPIHandler creates a new API handler
-func NewAPIHandler(monitorService *services.MonitorService, wsHub *services.WebSocketHub) *APIHandler {
+func NewAPIHandler(monitorService *services.MonitorService, rollupService *services.RollupService, wsHub *services.WebSocketHub) *APIHandler {
 	return &APIHandler{
 		monitorService: monitorService,
+		rollupService:  rollupService,
 		wsHub:          wsHub,
 	}
 }
@@ -60,13 +64,20 @@ func (h *APIHandler) CreateMonitor(c *gin.Context) {
 
 	// Create monitor in database
 	if err := h.monitorService.CreateMonitor(monitor); err != nil {
-		if err.Error() == "monitor with URL "+req.URL+" already exists" {
+		if strings.HasSuffix(err.Error(), "already exists") {
 			c.JSON(http.StatusConflict, gin.H{
 				"error":   "Monitor already exists",
 				"details": err.Error(),
 			})
 			return
 		}
+		if errors.Is(err, models.ErrInvalidMonitor) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid monitor settings",
+				"details": err.Error(),
+			})
+			return
+		}
 		
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create monitor",
@@ -87,6 +98,146 @@ func (h *APIHandler) CreateMonitor(c *gin.Context) {
 	})
 }
 
+// UpdateMonitor handles PUT/PATCH /api/v1/monitors/:id
+func (h *APIHandler) UpdateMonitor(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Convert string ID to ObjectID
+	objectID, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid monitor ID format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var req models.UpdateMonitorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Update monitor (this also restarts the monitoring job)
+	monitor, err := h.monitorService.UpdateMonitor(objectID, &req, h.wsHub)
+	if err != nil {
+		switch {
+		case err.Error() == "monitor not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Monitor not found",
+				"details": err.Error(),
+			})
+		case errors.Is(err, models.ErrInvalidMonitor):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid monitor settings",
+				"details": err.Error(),
+			})
+		case strings.HasSuffix(err.Error(), "already exists"):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Monitor already exists",
+				"details": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to update monitor",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Monitor updated successfully",
+		"data":    monitor,
+	})
+}
+
+// PauseMonitor handles POST /api/v1/monitors/:id/pause
+func (h *APIHandler) PauseMonitor(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Convert string ID to ObjectID
+	objectID, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid monitor ID format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// The request body is optional
+	var req models.PauseMonitorRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request data",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	monitor, err := h.monitorService.PauseMonitor(objectID, &req, h.wsHub)
+	if err != nil {
+		h.respondMonitorStateError(c, err, "Failed to pause monitor")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Monitor paused successfully",
+		"data":    monitor,
+	})
+}
+
+// ResumeMonitor handles POST /api/v1/monitors/:id/resume
+func (h *APIHandler) ResumeMonitor(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Convert string ID to ObjectID
+	objectID, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid monitor ID format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	monitor, err := h.monitorService.ResumeMonitor(objectID, h.wsHub)
+	if err != nil {
+		h.respondMonitorStateError(c, err, "Failed to resume monitor")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Monitor resumed successfully",
+		"data":    monitor,
+	})
+}
+
+// respondMonitorStateError writes the error response for pause/resume failures
+func (h *APIHandler) respondMonitorStateError(c *gin.Context, err error, message string) {
+	if err.Error() == "monitor not found" {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Monitor not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
+
 // DeleteMonitor handles DELETE /api/v1/monitors/:id
 func (h *APIHandler) DeleteMonitor(c *gin.Context) {
 	idParam := c.Param("id")
@@ -141,10 +292,59 @@ func (h *APIHandler) GetMetrics(c *gin.Context) {
 	// Get hours parameter (default to 24 hours)
 	hoursParam := c.DefaultQuery("hours", "24")
 	hours, err := strconv.Atoi(hoursParam)
-	if err != nil || hours < 1 || hours > 168 { // Max 1 week
+	if err != nil || hours < 1 || hours > models.MaxRetentionDays*24 {
 		hours = 24
 	}
 
+	// Raw checks for short ranges, rollups for longer ones unless asked otherwise
+	resolution := c.DefaultQuery("resolution", "auto")
+	if resolution == "auto" {
+		monitor, err := h.monitorService.GetMonitor(objectID)
+		if err != nil {
+			if err.Error() == "monitor not found" {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error":   "Monitor not found",
+					"details": err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to retrieve metrics",
+				"details": err.Error(),
+			})
+			return
+		}
+		resolution = h.rollupService.ResolutionFor(hours, monitor.Interval)
+	}
+	if resolution != "raw" && models.ResolutionDuration(resolution) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid resolution",
+			"details": "resolution must be auto, raw, 1m, 1h or 1d",
+		})
+		return
+	}
+
+	if resolution != "raw" {
+		rollups, err := h.monitorService.GetRollups(objectID, resolution, hours)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to retrieve metrics",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success":    true,
+			"data":       rollups,
+			"summary":    calculateRollupSummary(rollups),
+			"count":      len(rollups),
+			"hours":      hours,
+			"resolution": resolution,
+		})
+		return
+	}
+
 	// Retrieve metrics
 	metrics, err := h.monitorService.GetMetrics(objectID, hours)
 	if err != nil {
@@ -159,17 +359,18 @@ func (h *APIHandler) GetMetrics(c *gin.Context) {
 	summary := calculateMetricsSummary(metrics)
 
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    metrics,
-		"summary": summary,
-		"count":   len(metrics),
-		"hours":   hours,
+		"success":    true,
+		"data":       metrics,
+		"summary":    summary,
+		"count":      len(metrics),
+		"hours":      hours,
+		"resolution": resolution,
 	})
 }
 
 // GetDashboardStats handles GET /api/v1/dashboard/stats
 func (h *APIHandler) GetDashboardStats(c *gin.Context) {
-    monitors, err := h.monitorService.GetMonitors()
+    stats, err := h.monitorService.GetDashboardStats()
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{
             "error":   "Failed to retrieve dashboard stats",
@@ -178,45 +379,6 @@ func (h *APIHandler) GetDashboardStats(c *gin.Context) {
         return
     }
 
-    // Calculate dashboard statistics
-    stats := models.DashboardStats{
-        TotalMonitors:  len(monitors),
-        ActiveMonitors: 0,
-        UpMonitors:     0,
-        DownMonitors:   0,
-        OverallUptime:  0.0,
-        AverageResponse: 0.0,
-    }
-
-    var totalUptime float64 = 0
-    var totalResponseTime int64 = 0
-    var activeCount int = 0
-
-    for _, monitor := range monitors {
-        if monitor.IsActive {
-            stats.ActiveMonitors++
-            activeCount++
-            
-            // 🔥 Use switch instead of if-else
-            switch monitor.CurrentStatus {
-            case "up":
-                stats.UpMonitors++
-            case "down":
-                stats.DownMonitors++
-            // You could add more cases here like "unknown", "warning", etc.
-            }
-
-            totalUptime += monitor.UptimePercentage
-            totalResponseTime += int64(monitor.CurrentResponse)
-        }
-    }
-
-    // Calculate averages
-    if activeCount > 0 {
-        stats.OverallUptime = totalUptime / float64(activeCount)
-        stats.AverageResponse = float64(totalResponseTime) / float64(activeCount)
-    }
-
     c.JSON(http.StatusOK, gin.H{
         "success": true,
         "data":    stats,
@@ -229,7 +391,9 @@ func calculateMetricsSummary(metrics []models.Metric) map[string]interface{} {
 		return map[string]interface{}{
 			"total_checks":       0,
 			"successful_checks":  0,
+			"degraded_checks":    0,
 			"failed_checks":      0,
+			"maintenance_checks": 0,
 			"uptime_percentage":  0.0,
 			"average_response":   0.0,
 			"min_response":       0,
@@ -237,16 +401,24 @@ func calculateMetricsSummary(metrics []models.Metric) map[string]interface{} {
 		}
 	}
 
-	var successfulChecks, failedChecks int
+	var successfulChecks, degradedChecks, failedChecks, maintenanceChecks int
 	var totalResponseTime, minResponse, maxResponse int64
 	
 	minResponse = metrics[0].ResponseTime
 	maxResponse = metrics[0].ResponseTime
 
 	for _, metric := range metrics {
-		if metric.Status == "up" {
+		// Degraded checks still reached the endpoint, and checks during
+		// maintenance windows do not count towards uptime
+		switch {
+		case metric.InMaintenance:
+			maintenanceChecks++
+		case metric.Status != "down":
 			successfulChecks++
-		} else {
+			if metric.Status == "degraded" {
+				degradedChecks++
+			}
+		default:
 			failedChecks++
 		}
 
@@ -260,16 +432,67 @@ func calculateMetricsSummary(metrics []models.Metric) map[string]interface{} {
 		}
 	}
 
-	uptimePercentage := float64(successfulChecks) / float64(len(metrics)) * 100
+	uptimePercentage := 100.0
+	if counted := successfulChecks + failedChecks; counted > 0 {
+		uptimePercentage = float64(successfulChecks) / float64(counted) * 100
+	}
 	averageResponse := float64(totalResponseTime) / float64(len(metrics))
 
 	return map[string]interface{}{
 		"total_checks":       len(metrics),
 		"successful_checks":  successfulChecks,
+		"degraded_checks":    degradedChecks,
 		"failed_checks":      failedChecks,
+		"maintenance_checks": maintenanceChecks,
 		"uptime_percentage":  uptimePercentage,
 		"average_response":   averageResponse,
 		"min_response":       minResponse,
 		"max_response":       maxResponse,
 	}
-}
\ No newline at end of file
+}
+
+// calculateRollupSummary computes the same statistics as
+// calculateMetricsSummary from rollups
+func calculateRollupSummary(rollups []models.MetricRollup) map[string]interface{} {
+	if len(rollups) == 0 {
+		return calculateMetricsSummary(nil)
+	}
+
+	var totalChecks, successfulChecks, degradedChecks, failedChecks, maintenanceChecks int
+	var totalResponseTime float64
+	minResponse := rollups[0].MinResponse
+	maxResponse := rollups[0].MaxResponse
+
+	for _, rollup := range rollups {
+		totalChecks += rollup.Count
+		successfulChecks += rollup.UpCount + rollup.DegradedCount
+		degradedChecks += rollup.DegradedCount
+		failedChecks += rollup.DownCount
+		maintenanceChecks += rollup.MaintenanceCount
+		totalResponseTime += rollup.AvgResponse * float64(rollup.Count)
+
+		if rollup.MinResponse < minResponse {
+			minResponse = rollup.MinResponse
+		}
+		if rollup.MaxResponse > maxResponse {
+			maxResponse = rollup.MaxResponse
+		}
+	}
+
+	uptimePercentage := 100.0
+	if counted := successfulChecks + failedChecks; counted > 0 {
+		uptimePercentage = float64(successfulChecks) / float64(counted) * 100
+	}
+
+	return map[string]interface{}{
+		"total_checks":       totalChecks,
+		"successful_checks":  successfulChecks,
+		"degraded_checks":    degradedChecks,
+		"failed_checks":      failedChecks,
+		"maintenance_checks": maintenanceChecks,
+		"uptime_percentage":  uptimePercentage,
+		"average_response":   totalResponseTime / float64(totalChecks),
+		"min_response":       minResponse,
+		"max_response":       maxResponse,
+	}
+}
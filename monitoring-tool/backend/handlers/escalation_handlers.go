@@ -0,0 +1,111 @@
+// handlers/escalation_handlers.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/models"
+	"monitoring-tool/services"
+)
+
+type EscalationHandler struct {
+	escalationService *services.EscalationService
+}
+
+// NewEscalationHandler creates a new escalation policy handler
+func NewEscalationHandler(escalationService *services.EscalationService) *EscalationHandler {
+	return &EscalationHandler{
+		escalationService: escalationService,
+	}
+}
+
+// GetPolicies handles GET /api/v1/escalation-policies
+func (h *EscalationHandler) GetPolicies(c *gin.Context) {
+	policies, err := h.escalationService.GetPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve escalation policies",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    policies,
+		"count":   len(policies),
+	})
+}
+
+// CreatePolicy handles POST /api/v1/escalation-policies
+func (h *EscalationHandler) CreatePolicy(c *gin.Context) {
+	var req models.CreateEscalationPolicyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	policy := req.ToPolicy()
+	if err := h.escalationService.CreatePolicy(policy); err != nil {
+		if errors.Is(err, models.ErrInvalidEscalationPolicy) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid escalation policy",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create escalation policy",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Escalation policy created successfully",
+		"data":    policy,
+	})
+}
+
+// DeletePolicy handles DELETE /api/v1/escalation-policies/:id
+func (h *EscalationHandler) DeletePolicy(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid escalation policy ID format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.escalationService.DeletePolicy(objectID); err != nil {
+		if err.Error() == "escalation policy not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Escalation policy not found",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete escalation policy",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Escalation policy deleted successfully",
+	})
+}
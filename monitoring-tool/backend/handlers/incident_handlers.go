@@ -0,0 +1,145 @@
+// handlers/incident_handlers.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/models"
+	"monitoring-tool/services"
+)
+
+type IncidentHandler struct {
+	incidentService *services.IncidentService
+	wsHub           *services.WebSocketHub
+}
+
+// NewIncidentHandler creates a new incident handler
+func NewIncidentHandler(incidentService *services.IncidentService, wsHub *services.WebSocketHub) *IncidentHandler {
+	return &IncidentHandler{
+		incidentService: incidentService,
+		wsHub:           wsHub,
+	}
+}
+
+// GetIncidents handles GET /api/v1/incidents
+func (h *IncidentHandler) GetIncidents(c *gin.Context) {
+	// Optional status filter (open, resolved)
+	status := c.Query("status")
+	if status != "" && status != "open" && status != "resolved" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid status filter",
+			"details": "status must be open or resolved",
+		})
+		return
+	}
+
+	incidents, err := h.incidentService.GetIncidents(status, incidentLimit(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve incidents",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    incidents,
+		"count":   len(incidents),
+	})
+}
+
+// GetMonitorIncidents handles GET /api/v1/monitors/:id/incidents
+func (h *IncidentHandler) GetMonitorIncidents(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Convert string ID to ObjectID
+	objectID, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid monitor ID format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	incidents, err := h.incidentService.GetMonitorIncidents(objectID, incidentLimit(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve incidents",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    incidents,
+		"count":   len(incidents),
+	})
+}
+
+// AcknowledgeIncident handles POST /api/v1/incidents/:id/acknowledge
+func (h *IncidentHandler) AcknowledgeIncident(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid incident ID format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// The request body is optional
+	var req models.AcknowledgeIncidentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request data",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	incident, err := h.incidentService.AcknowledgeIncident(objectID, req.AcknowledgedBy, h.wsHub)
+	if err != nil {
+		switch err.Error() {
+		case "incident not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Incident not found",
+				"details": err.Error(),
+			})
+		case "incident is already resolved", "incident is already acknowledged":
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Incident cannot be acknowledged",
+				"details": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to acknowledge incident",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Incident acknowledged",
+		"data":    incident,
+	})
+}
+
+// incidentLimit reads the limit query parameter (default 50, max 500)
+func incidentLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		limit = 50
+	}
+	return limit
+}
@@ -0,0 +1,111 @@
+// handlers/maintenance_handlers.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/models"
+	"monitoring-tool/services"
+)
+
+type MaintenanceHandler struct {
+	maintenanceService *services.MaintenanceService
+}
+
+// NewMaintenanceHandler creates a new maintenance window handler
+func NewMaintenanceHandler(maintenanceService *services.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// GetWindows handles GET /api/v1/maintenance-windows
+func (h *MaintenanceHandler) GetWindows(c *gin.Context) {
+	windows, err := h.maintenanceService.GetWindows()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve maintenance windows",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    windows,
+		"count":   len(windows),
+	})
+}
+
+// CreateWindow handles POST /api/v1/maintenance-windows
+func (h *MaintenanceHandler) CreateWindow(c *gin.Context) {
+	var req models.CreateMaintenanceWindowRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	window := req.ToWindow()
+	if err := h.maintenanceService.CreateWindow(window); err != nil {
+		if errors.Is(err, models.ErrInvalidMaintenanceWindow) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid maintenance window settings",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create maintenance window",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Maintenance window created successfully",
+		"data":    window,
+	})
+}
+
+// DeleteWindow handles DELETE /api/v1/maintenance-windows/:id
+func (h *MaintenanceHandler) DeleteWindow(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid maintenance window ID format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.maintenanceService.DeleteWindow(objectID); err != nil {
+		if err.Error() == "maintenance window not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Maintenance window not found",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete maintenance window",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Maintenance window deleted successfully",
+	})
+}
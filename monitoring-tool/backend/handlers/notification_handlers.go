@@ -0,0 +1,184 @@
+// handlers/notification_handlers.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/models"
+	"monitoring-tool/services"
+)
+
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetChannels handles GET /api/v1/notifications/channels
+func (h *NotificationHandler) GetChannels(c *gin.Context) {
+	channels, err := h.notificationService.GetChannels()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve notification channels",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    channels,
+		"count":   len(channels),
+	})
+}
+
+// CreateChannel handles POST /api/v1/notifications/channels
+func (h *NotificationHandler) CreateChannel(c *gin.Context) {
+	var req models.CreateChannelRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	channel := req.ToChannel()
+	if err := h.notificationService.CreateChannel(channel); err != nil {
+		if errors.Is(err, models.ErrInvalidChannel) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid channel settings",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create notification channel",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Notification channel created successfully",
+		"data":    channel,
+	})
+}
+
+// DeleteChannel handles DELETE /api/v1/notifications/channels/:id
+func (h *NotificationHandler) DeleteChannel(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid channel ID format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.notificationService.DeleteChannel(objectID); err != nil {
+		respondChannelError(c, err, "Failed to delete notification channel")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Notification channel deleted successfully",
+	})
+}
+
+// TestChannel handles POST /api/v1/notifications/channels/:id/test
+func (h *NotificationHandler) TestChannel(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid channel ID format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	delivery, err := h.notificationService.TestChannel(objectID)
+	if err != nil {
+		respondChannelError(c, err, "Failed to send test notification")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": delivery.Status == "delivered",
+		"data":    delivery,
+	})
+}
+
+// GetDeliveries handles GET /api/v1/notifications/deliveries
+func (h *NotificationHandler) GetDeliveries(c *gin.Context) {
+	var channelID *primitive.ObjectID
+	if idParam := c.Query("channel_id"); idParam != "" {
+		objectID, err := primitive.ObjectIDFromHex(idParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid channel ID format",
+				"details": err.Error(),
+			})
+			return
+		}
+		channelID = &objectID
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > 1000 {
+		limit = 100
+	}
+
+	deliveries, err := h.notificationService.GetDeliveries(channelID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve notification deliveries",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    deliveries,
+		"count":   len(deliveries),
+	})
+}
+
+// respondChannelError writes the error response for channel lookups
+func respondChannelError(c *gin.Context, err error, message string) {
+	if err.Error() == "channel not found" {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Notification channel not found",
+			"details": err.Error(),
+		})
+		return
+	}
+	if strings.HasPrefix(err.Error(), "channel is still used by") {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Notification channel is in use",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
@@ -0,0 +1,117 @@
+// jsonpath/jsonpath.go
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path is a compiled JSONPath expression.
+//
+// Only the subset needed for health-check assertions is supported:
+// a leading "$" followed by dot members ($.db.status), bracket members
+// ($['queue-depth']) and array indexes ($.checks[0], $.checks[-1]).
+type Path struct {
+	expr     string
+	segments []segment
+}
+
+// segment is a single member name or array index in a path
+type segment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// Compile parses a JSONPath expression
+func Compile(expr string) (*Path, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", expr)
+	}
+
+	path := &Path{expr: expr}
+	rest := expr[1:]
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("JSONPath %q has an empty member name", expr)
+			}
+			path.segments = append(path.segments, segment{key: rest[:end]})
+			rest = rest[end:]
+
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("JSONPath %q has an unclosed bracket", expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				path.segments = append(path.segments, segment{key: inner[1 : len(inner)-1]})
+				continue
+			}
+
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("JSONPath %q has an invalid index %q", expr, inner)
+			}
+			path.segments = append(path.segments, segment{index: index, isIndex: true})
+
+		default:
+			return nil, fmt.Errorf("JSONPath %q has an unexpected character %q", expr, rest[0])
+		}
+	}
+
+	return path, nil
+}
+
+// String returns the original expression
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Lookup returns the value at the path in a document decoded with
+// encoding/json. The boolean is false when the path does not exist.
+func (p *Path) Lookup(document interface{}) (interface{}, bool) {
+	current := document
+
+	for _, seg := range p.segments {
+		if seg.isIndex {
+			array, ok := current.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			index := seg.index
+			if index < 0 {
+				index += len(array)
+			}
+			if index < 0 || index >= len(array) {
+				return nil, false
+			}
+			current = array[index]
+			continue
+		}
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, exists := object[seg.key]
+		if !exists {
+			return nil, false
+		}
+		current = value
+	}
+
+	return current, true
+}
@@ -0,0 +1,118 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// decode parses a document the way JSON assertions do
+func decode(t *testing.T, document string) interface{} {
+	t.Helper()
+
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		t.Fatalf("invalid test document %s: %v", document, err)
+	}
+	return value
+}
+
+func TestCompileInvalid(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"", "must start with $"},
+		{"db.status", "must start with $"},
+		{"$.", "empty member name"},
+		{"$..db", "empty member name"},
+		{"$.checks[0", "unclosed bracket"},
+		{"$.checks[first]", "invalid index"},
+		{"$.checks[]", "invalid index"},
+		{"$db", "unexpected character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if _, err := Compile(tt.expr); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Compile(%q) err = %v, want %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	health := `{
+		"status": "ok",
+		"db": {"status": "up", "latency": 12, "replica": {"lag": 0.5}},
+		"queue-depth": 3,
+		"checks": [{"name": "cache", "ok": true}, {"name": "disk", "ok": false}],
+		"matrix": [[1, 2], [3, 4]],
+		"empty": null
+	}`
+
+	tests := []struct {
+		name      string
+		document  string
+		path      string
+		want      string // the value found, as compact JSON
+		wantFound bool
+	}{
+		{"root", health, "$", "", true},
+		{"member", health, "$.status", `"ok"`, true},
+		{"nested member", health, "$.db.status", `"up"`, true},
+		{"deeply nested", health, "$.db.replica.lag", "0.5", true},
+		{"object value", health, "$.db.replica", `{"lag":0.5}`, true},
+		{"bracket member", health, "$['queue-depth']", "3", true},
+		{"double quoted bracket member", health, `$["db"].latency`, "12", true},
+		{"array index", health, "$.checks[0].name", `"cache"`, true},
+		{"negative index", health, "$.checks[-1].ok", "false", true},
+		{"nested arrays", health, "$.matrix[1][0]", "3", true},
+		{"null value", health, "$.empty", "null", true},
+
+		{"missing member", health, "$.cache", "", false},
+		{"missing nested member", health, "$.db.replica.primary", "", false},
+		{"index out of range", health, "$.checks[2]", "", false},
+		{"negative index out of range", health, "$.checks[-3]", "", false},
+		{"index on an object", health, "$.db[0]", "", false},
+		{"member of an array", health, "$.checks.name", "", false},
+		{"member of a string", health, "$.status.length", "", false},
+		{"member of null", health, "$.empty.status", "", false},
+
+		{"array root", `[{"status": "ok"}]`, "$[0].status", `"ok"`, true},
+		{"member of an array root", `[{"status": "ok"}]`, "$.status", "", false},
+		{"string root", `"ok"`, "$.status", "", false},
+		{"number root", `42`, "$[0]", "", false},
+		{"null root", `null`, "$.status", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := Compile(tt.path)
+			if err != nil {
+				t.Fatalf("Compile(%q) failed: %v", tt.path, err)
+			}
+			if path.String() != tt.path {
+				t.Errorf("String() = %q, want %q", path.String(), tt.path)
+			}
+
+			document := decode(t, tt.document)
+			value, found := path.Lookup(document)
+			if found != tt.wantFound {
+				t.Fatalf("Lookup(%s) found = %v, want %v", tt.path, found, tt.wantFound)
+			}
+			if !found || tt.want == "" {
+				return
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				t.Fatalf("failed to encode %v: %v", value, err)
+			}
+			if string(encoded) != tt.want {
+				t.Errorf("Lookup(%s) = %s, want %s", tt.path, encoded, tt.want)
+			}
+		})
+	}
+}
@@ -38,21 +38,29 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// Initialize database
-	db, err := database.InitMongoDB(cfg)
+	// Initialize storage
+	store, err := database.Open(cfg)
 	if err != nil {
-		log.Fatal("Failed to connect to MongoDB:", err)
+		log.Fatal("Failed to open storage:", err)
 	}
-	defer db.Disconnect(context.Background())
+	defer store.Close(context.Background())
 
 	// Initialize MonitorService with max concurrent jobs
 	maxConcurrentJobs := 10 // adjust as needed
-	monitorService := services.NewMonitorService(db, maxConcurrentJobs)
+	incidentService := services.NewIncidentService(store)
+	notificationService := services.NewNotificationService(store, cfg)
+	maintenanceService := services.NewMaintenanceService(store)
+	escalationService := services.NewEscalationService(store, incidentService, notificationService, maintenanceService)
+	rollupService := services.NewRollupService(store, cfg)
+	flapDetector := services.NewFlapDetector(cfg.FlapWindow, cfg.FlapStartThreshold, cfg.FlapStopThreshold)
+	monitorService := services.NewMonitorService(store, incidentService, notificationService, escalationService, flapDetector, maintenanceService, maxConcurrentJobs, cfg.GetMetricsRetentionDuration())
 
 	// Initialize WebSocket hub
 	wsHub := services.NewWebSocketHub()
 	go wsHub.Run()
 	go monitorService.StartMonitoring(wsHub)
+	go escalationService.Run()
+	go rollupService.Run()
 
 	// Initialize router
 	var r *gin.Engine
@@ -87,23 +95,45 @@ func main() {
         "http://localhost:5173", 
         "https://monitoring-dashboard-csiy.vercel.app", // ✅ your frontend URL
     },
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
 	// Initialize handlers
-	apiHandler := handlers.NewAPIHandler(monitorService, wsHub)
+	apiHandler := handlers.NewAPIHandler(monitorService, rollupService, wsHub)
 	wsHandler := handlers.NewWebSocketHandler(wsHub)
+	incidentHandler := handlers.NewIncidentHandler(incidentService, wsHub)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	escalationHandler := handlers.NewEscalationHandler(escalationService)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceService)
 
 	// API routes
 	api := r.Group("/api/v1")
 	{
 		api.GET("/monitors", apiHandler.GetMonitors)
 		api.POST("/monitors", apiHandler.CreateMonitor)
+		api.PUT("/monitors/:id", apiHandler.UpdateMonitor)
+		api.PATCH("/monitors/:id", apiHandler.UpdateMonitor)
 		api.DELETE("/monitors/:id", apiHandler.DeleteMonitor)
+		api.POST("/monitors/:id/pause", apiHandler.PauseMonitor)
+		api.POST("/monitors/:id/resume", apiHandler.ResumeMonitor)
 		api.GET("/monitors/:id/metrics", apiHandler.GetMetrics)
+		api.GET("/monitors/:id/incidents", incidentHandler.GetMonitorIncidents)
+		api.GET("/incidents", incidentHandler.GetIncidents)
+		api.POST("/incidents/:id/acknowledge", incidentHandler.AcknowledgeIncident)
+		api.GET("/notifications/channels", notificationHandler.GetChannels)
+		api.POST("/notifications/channels", notificationHandler.CreateChannel)
+		api.DELETE("/notifications/channels/:id", notificationHandler.DeleteChannel)
+		api.POST("/notifications/channels/:id/test", notificationHandler.TestChannel)
+		api.GET("/notifications/deliveries", notificationHandler.GetDeliveries)
+		api.GET("/escalation-policies", escalationHandler.GetPolicies)
+		api.POST("/escalation-policies", escalationHandler.CreatePolicy)
+		api.DELETE("/escalation-policies/:id", escalationHandler.DeletePolicy)
+		api.GET("/maintenance-windows", maintenanceHandler.GetWindows)
+		api.POST("/maintenance-windows", maintenanceHandler.CreateWindow)
+		api.DELETE("/maintenance-windows/:id", maintenanceHandler.DeleteWindow)
 		api.GET("/dashboard/stats", apiHandler.GetDashboardStats)
 		api.GET("/health", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{
@@ -164,5 +194,9 @@ func main() {
 		log.Fatal("Server forced to shutdown:", err)
 	}
 
+	// Let background work finish before the deferred store close
+	escalationService.Stop()
+	rollupService.Stop()
+
 	log.Println("✅ Server exited")
 }
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EscalationPolicy routes a monitor's incidents through an ordered list of
+// notification steps until the incident is acknowledged or resolved
+type EscalationPolicy struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name      string             `json:"name" bson:"name"`
+	Steps     []EscalationStep   `json:"steps" bson:"steps"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// EscalationStep notifies its channels once an incident has been open and
+// unacknowledged for DelayMinutes
+type EscalationStep struct {
+	ChannelIDs   []primitive.ObjectID `json:"channel_ids" bson:"channel_ids"`
+	DelayMinutes int                  `json:"delay_minutes" bson:"delay_minutes"` // since the incident started
+}
+
+// CreateEscalationPolicyRequest represents the request to create an escalation policy
+type CreateEscalationPolicyRequest struct {
+	Name  string           `json:"name" binding:"required"`
+	Steps []EscalationStep `json:"steps"`
+}
+
+// AcknowledgeIncidentRequest represents the optional body of an acknowledge request
+type AcknowledgeIncidentRequest struct {
+	AcknowledgedBy string `json:"acknowledged_by"`
+}
+
+// ErrInvalidEscalationPolicy is wrapped by all escalation policy validation errors
+var ErrInvalidEscalationPolicy = errors.New("invalid escalation policy")
+
+// ToPolicy converts a request to an EscalationPolicy model
+func (req *CreateEscalationPolicyRequest) ToPolicy() *EscalationPolicy {
+	now := time.Now()
+
+	return &EscalationPolicy{
+		Name:      req.Name,
+		Steps:     req.Steps,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate checks that the policy has at least one step and that steps are
+// ordered by delay
+func (p *EscalationPolicy) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidEscalationPolicy)
+	}
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("%w: at least one step is required", ErrInvalidEscalationPolicy)
+	}
+
+	previousDelay := 0
+	for i, step := range p.Steps {
+		if len(step.ChannelIDs) == 0 {
+			return fmt.Errorf("%w: step %d has no channels", ErrInvalidEscalationPolicy, i+1)
+		}
+		if step.DelayMinutes < previousDelay {
+			return fmt.Errorf("%w: step %d has a shorter delay than the step before it", ErrInvalidEscalationPolicy, i+1)
+		}
+		previousDelay = step.DelayMinutes
+	}
+
+	return nil
+}
+
+// StepChannelIDs returns the channels of the first n steps, without duplicates
+func (p *EscalationPolicy) StepChannelIDs(n int) []primitive.ObjectID {
+	seen := map[primitive.ObjectID]bool{}
+	ids := []primitive.ObjectID{}
+	for _, step := range p.Steps[:min(n, len(p.Steps))] {
+		for _, id := range step.ChannelIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Incident represents an outage, from a monitor going down until it recovers
+type Incident struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	MonitorID      primitive.ObjectID `json:"monitor_id" bson:"monitor_id"`
+	MonitorName    string             `json:"monitor_name" bson:"monitor_name"`
+	URL            string             `json:"url" bson:"url"`
+	Status         string             `json:"status" bson:"status"` // open, resolved
+	StartedAt      time.Time          `json:"started_at" bson:"started_at"`
+	ResolvedAt     *time.Time         `json:"resolved_at,omitempty" bson:"resolved_at,omitempty"`
+	Duration       int64              `json:"duration" bson:"duration"` // seconds, set when resolved
+	FirstError     string             `json:"first_error,omitempty" bson:"first_error,omitempty"`
+	LastError      string             `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	AffectedChecks int                `json:"affected_checks" bson:"affected_checks"` // failed checks during the incident
+
+	// Escalation (monitors with an escalation policy only)
+	EscalationPolicyID *primitive.ObjectID `json:"escalation_policy_id,omitempty" bson:"escalation_policy_id,omitempty"`
+	EscalationLevel    int                 `json:"escalation_level" bson:"escalation_level"` // escalation steps notified so far
+	AcknowledgedAt     *time.Time          `json:"acknowledged_at,omitempty" bson:"acknowledged_at,omitempty"`
+	AcknowledgedBy     string              `json:"acknowledged_by,omitempty" bson:"acknowledged_by,omitempty"`
+	// Escalation is held while the monitor is paused, under maintenance or
+	// flapping. Once a hold ends, step delays count from EscalationStartedAt
+	// instead of StartedAt, so overdue steps are not all sent at once.
+	EscalationHeldAt    *time.Time `json:"escalation_held_at,omitempty" bson:"escalation_held_at,omitempty"`
+	EscalationStartedAt *time.Time `json:"escalation_started_at,omitempty" bson:"escalation_started_at,omitempty"`
+}
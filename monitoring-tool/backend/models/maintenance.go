@@ -0,0 +1,144 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/cron"
+)
+
+// MaintenanceWindow is a planned period during which checks of the affected
+// monitors do not open incidents, send notifications or count against uptime.
+// A window is either one-off (starts_at/ends_at) or recurring (a cron
+// schedule with a duration).
+type MaintenanceWindow struct {
+	ID         primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	Name       string               `json:"name" bson:"name"`
+	MonitorIDs []primitive.ObjectID `json:"monitor_ids,omitempty" bson:"monitor_ids,omitempty"`
+	Tags       []string             `json:"tags,omitempty" bson:"tags,omitempty"` // applies to monitors with any of these tags
+
+	// One-off window
+	StartsAt *time.Time `json:"starts_at,omitempty" bson:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty" bson:"ends_at,omitempty"`
+
+	// Recurring window
+	Schedule        string `json:"schedule,omitempty" bson:"schedule,omitempty"` // cron expression for the start of each window, e.g. "0 2 * * 0"
+	DurationMinutes int    `json:"duration_minutes,omitempty" bson:"duration_minutes,omitempty"`
+	Timezone        string `json:"timezone,omitempty" bson:"timezone,omitempty"` // IANA name the schedule is evaluated in, default UTC
+
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// CreateMaintenanceWindowRequest represents the request to create a maintenance window
+type CreateMaintenanceWindowRequest struct {
+	Name       string               `json:"name" binding:"required"`
+	MonitorIDs []primitive.ObjectID `json:"monitor_ids"`
+	Tags       []string             `json:"tags"`
+
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+
+	Schedule        string `json:"schedule"`
+	DurationMinutes int    `json:"duration_minutes"`
+	Timezone        string `json:"timezone"`
+}
+
+// ErrInvalidMaintenanceWindow is wrapped by all maintenance window validation errors
+var ErrInvalidMaintenanceWindow = errors.New("invalid maintenance window")
+
+// ToWindow converts a request to a MaintenanceWindow model
+func (req *CreateMaintenanceWindowRequest) ToWindow() *MaintenanceWindow {
+	now := time.Now()
+
+	return &MaintenanceWindow{
+		Name:            req.Name,
+		MonitorIDs:      req.MonitorIDs,
+		Tags:            req.Tags,
+		StartsAt:        req.StartsAt,
+		EndsAt:          req.EndsAt,
+		Schedule:        strings.TrimSpace(req.Schedule),
+		DurationMinutes: req.DurationMinutes,
+		Timezone:        req.Timezone,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// Validate checks that the window targets monitors and has exactly one of a
+// one-off period or a recurring schedule
+func (w *MaintenanceWindow) Validate() error {
+	if strings.TrimSpace(w.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidMaintenanceWindow)
+	}
+	if len(w.MonitorIDs) == 0 && len(w.Tags) == 0 {
+		return fmt.Errorf("%w: monitor_ids or tags is required", ErrInvalidMaintenanceWindow)
+	}
+
+	oneOff := w.StartsAt != nil || w.EndsAt != nil
+	recurring := w.Schedule != ""
+	switch {
+	case oneOff && recurring:
+		return fmt.Errorf("%w: use either starts_at/ends_at or schedule, not both", ErrInvalidMaintenanceWindow)
+	case oneOff:
+		if w.StartsAt == nil || w.EndsAt == nil || !w.EndsAt.After(*w.StartsAt) {
+			return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidMaintenanceWindow)
+		}
+	case recurring:
+		if _, err := cron.Parse(w.Schedule); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidMaintenanceWindow, err)
+		}
+		if w.DurationMinutes < 1 {
+			return fmt.Errorf("%w: duration_minutes must be at least 1", ErrInvalidMaintenanceWindow)
+		}
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("%w: unknown timezone %q", ErrInvalidMaintenanceWindow, w.Timezone)
+		}
+	default:
+		return fmt.Errorf("%w: starts_at/ends_at or schedule is required", ErrInvalidMaintenanceWindow)
+	}
+
+	return nil
+}
+
+// AppliesTo reports whether the window covers the monitor, by ID or tag
+func (w *MaintenanceWindow) AppliesTo(monitor Monitor) bool {
+	for _, id := range w.MonitorIDs {
+		if id == monitor.ID {
+			return true
+		}
+	}
+	for _, tag := range w.Tags {
+		for _, monitorTag := range monitor.Tags {
+			if tag == monitorTag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ActiveAt reports whether the window is in effect at t
+func (w *MaintenanceWindow) ActiveAt(t time.Time) bool {
+	if w.Schedule == "" {
+		return w.StartsAt != nil && w.EndsAt != nil && !t.Before(*w.StartsAt) && t.Before(*w.EndsAt)
+	}
+
+	schedule, err := cron.Parse(w.Schedule)
+	if err != nil {
+		return false
+	}
+	location, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return false
+	}
+
+	// The window is open if a scheduled start falls within the last duration
+	duration := time.Duration(w.DurationMinutes) * time.Minute
+	start := schedule.Next(t.In(location).Add(-duration))
+	return !start.IsZero() && !start.After(t)
+}
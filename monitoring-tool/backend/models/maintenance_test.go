@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // the timezone case needs the zone database on every platform
+)
+
+func TestMaintenanceWindowActiveAt(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("failed to load zone: %v", err)
+	}
+
+	// Sunday 7 January 2024, 02:00 UTC
+	start := time.Date(2024, 1, 7, 2, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	weekly := MaintenanceWindow{Schedule: "0 2 * * 0", DurationMinutes: 90}
+	oneOff := MaintenanceWindow{StartsAt: &start, EndsAt: &end}
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		at     time.Time
+		want   bool
+	}{
+		{"recurring before start", weekly, start.Add(-time.Second), false},
+		{"recurring at start", weekly, start, true},
+		{"recurring during", weekly, start.Add(45 * time.Minute), true},
+		{"recurring just before end", weekly, end.Add(-time.Second), true},
+		{"recurring at end of duration", weekly, end, false},
+		{"recurring on a day off schedule", weekly, start.Add(24 * time.Hour), false},
+		{"recurring next week", weekly, start.Add(7 * 24 * time.Hour), true},
+		{"recurring in UTC when no timezone", weekly, time.Date(2024, 1, 7, 3, 0, 0, 0, berlin), true},
+
+		// 02:00 in Berlin is 01:00 UTC in winter
+		{"timezone at start", MaintenanceWindow{Schedule: "0 2 * * 0", DurationMinutes: 90, Timezone: "Europe/Berlin"}, start.Add(-time.Hour), true},
+		{"timezone at end", MaintenanceWindow{Schedule: "0 2 * * 0", DurationMinutes: 90, Timezone: "Europe/Berlin"}, end.Add(-time.Hour), false},
+		{"timezone at UTC start", MaintenanceWindow{Schedule: "0 2 * * 0", DurationMinutes: 30, Timezone: "Europe/Berlin"}, start, false},
+
+		{"one-off before start", oneOff, start.Add(-time.Second), false},
+		{"one-off at start", oneOff, start, true},
+		{"one-off just before end", oneOff, end.Add(-time.Second), true},
+		{"one-off at end", oneOff, end, false},
+
+		{"invalid schedule", MaintenanceWindow{Schedule: "bad", DurationMinutes: 90}, start, false},
+		{"unknown timezone", MaintenanceWindow{Schedule: "0 2 * * 0", DurationMinutes: 90, Timezone: "Mars/Base"}, start, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.ActiveAt(tt.at); got != tt.want {
+				t.Errorf("ActiveAt(%s) = %v, want %v", tt.at.UTC().Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+}
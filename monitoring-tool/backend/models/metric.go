@@ -11,11 +11,63 @@ type Metric struct {
 	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	MonitorID    primitive.ObjectID `json:"monitor_id" bson:"monitor_id"`
 	URL          string             `json:"url" bson:"url"`
-	Status       string             `json:"status" bson:"status"`             // up, down
-	StatusCode   int                `json:"status_code" bson:"status_code"`   // HTTP status code
-	ResponseTime int64              `json:"response_time" bson:"response_time"` // milliseconds
+	Status       string             `json:"status" bson:"status"`             // up, degraded, down
+	StatusCode   int                `json:"status_code" bson:"status_code"`   // HTTP status code (0 for tcp checks)
+	ResponseTime int64              `json:"response_time" bson:"response_time"` // milliseconds (connect latency for tcp checks)
 	Error        string             `json:"error,omitempty" bson:"error,omitempty"`
+	DNSAnswers   []string           `json:"dns_answers,omitempty" bson:"dns_answers,omitempty"` // records returned by dns checks
+	TLS          *TLSInfo           `json:"tls,omitempty" bson:"tls,omitempty"`                 // certificate presented by https checks
+	RedirectChain []string          `json:"redirect_chain,omitempty" bson:"redirect_chain,omitempty"` // every URL visited when redirects were followed
+	FinalURL     string             `json:"final_url,omitempty" bson:"final_url,omitempty"`
+	Timing       *RequestTiming     `json:"timing,omitempty" bson:"timing,omitempty"`           // phase breakdown of http checks
+	Attempts     []CheckAttempt     `json:"attempts,omitempty" bson:"attempts,omitempty"`       // every attempt made when a failure was retried
+	AssertionResults []AssertionResult `json:"assertion_results,omitempty" bson:"assertion_results,omitempty"` // per-assertion outcome of JSON assertions
+	InMaintenance bool              `json:"in_maintenance,omitempty" bson:"in_maintenance,omitempty"` // checked during a maintenance window, excluded from uptime
 	CheckedAt    time.Time          `json:"checked_at" bson:"checked_at"`
+	ExpiresAt    time.Time          `json:"-" bson:"expires_at"` // removed by the TTL index at this time
+}
+
+// TLSInfo describes the leaf certificate presented by an HTTPS endpoint
+type TLSInfo struct {
+	Subject       string    `json:"subject" bson:"subject"`
+	Issuer        string    `json:"issuer" bson:"issuer"`
+	DNSNames      []string  `json:"dns_names" bson:"dns_names"`
+	NotBefore     time.Time `json:"not_before" bson:"not_before"`
+	NotAfter      time.Time `json:"not_after" bson:"not_after"`
+	DaysRemaining int       `json:"days_remaining" bson:"days_remaining"`
+	ChainValid    bool      `json:"chain_valid" bson:"chain_valid"`       // chains to a trusted root and is within its validity period
+	HostnameValid bool      `json:"hostname_valid" bson:"hostname_valid"` // certificate covers the monitored hostname
+}
+
+// CheckAttempt is the outcome of one attempt of a retried check
+type CheckAttempt struct {
+	Status       string    `json:"status" bson:"status"`
+	StatusCode   int       `json:"status_code" bson:"status_code"`
+	ResponseTime int64     `json:"response_time" bson:"response_time"`
+	Error        string    `json:"error,omitempty" bson:"error,omitempty"`
+	CheckedAt    time.Time `json:"checked_at" bson:"checked_at"`
+}
+
+// RequestTiming breaks an HTTP check down into phases, in milliseconds.
+// DNS, connect and TLS are zero when a pooled connection was reused, and
+// are summed across hops when redirects were followed.
+type RequestTiming struct {
+	DNSLookup       int64 `json:"dns_lookup" bson:"dns_lookup"`
+	TCPConnect      int64 `json:"tcp_connect" bson:"tcp_connect"`
+	TLSHandshake    int64 `json:"tls_handshake" bson:"tls_handshake"`
+	TimeToFirstByte int64 `json:"time_to_first_byte" bson:"time_to_first_byte"` // request written to first response byte (server processing)
+	ContentTransfer int64 `json:"content_transfer" bson:"content_transfer"`     // first response byte to end of body
+	Total           int64 `json:"total" bson:"total"`
+}
+
+// AssertionResult records the outcome of a single JSON assertion
+type AssertionResult struct {
+	Path     string `json:"path" bson:"path"`
+	Operator string `json:"operator" bson:"operator"`
+	Expected string `json:"expected,omitempty" bson:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty" bson:"actual,omitempty"`
+	Passed   bool   `json:"passed" bson:"passed"`
+	Error    string `json:"error,omitempty" bson:"error,omitempty"`
 }
 
 // WebSocketMessage represents real-time updates sent via WebSocket
@@ -28,21 +80,47 @@ type WebSocketMessage struct {
 // MonitorUpdate represents live status updates
 type MonitorUpdate struct {
 	MonitorID        string    `json:"monitor_id"`
-	Status           string    `json:"status"`           // up, down
+	Status           string    `json:"status"`           // up, degraded, down
 	StatusCode       int       `json:"status_code"`
 	ResponseTime     int64     `json:"response_time"`
 	URL              string    `json:"url"`
 	Error            string    `json:"error,omitempty"`
+	TLS              *TLSInfo  `json:"tls,omitempty"`
+	AssertionResults []AssertionResult `json:"assertion_results,omitempty"`
+	Timing           *RequestTiming    `json:"timing,omitempty"`
+	InMaintenance    bool      `json:"in_maintenance,omitempty"`
 	Timestamp        time.Time `json:"timestamp"`
 	UptimePercentage float64   `json:"uptime_percentage"`
 }
 
+// MonitorStatusUpdate represents a change to a monitor's active/paused state
+type MonitorStatusUpdate struct {
+	MonitorID   string     `json:"monitor_id"`
+	Status      string     `json:"status"` // active, paused
+	IsActive    bool       `json:"is_active"`
+	PausedAt    *time.Time `json:"paused_at,omitempty"`
+	PausedBy    string     `json:"paused_by,omitempty"`
+	PauseReason string     `json:"pause_reason,omitempty"`
+	Timestamp   time.Time  `json:"timestamp"`
+}
+
+// FlappingUpdate announces that a monitor started or stopped flapping
+type FlappingUpdate struct {
+	MonitorID  string    `json:"monitor_id"`
+	IsFlapping bool      `json:"is_flapping"`
+	Status     string    `json:"status"`      // status of the check that changed the flapping state
+	ChangeRate float64   `json:"change_rate"` // share of recent checks that changed state, 0-1
+	Timestamp  time.Time `json:"timestamp"`
+}
+
 // DashboardStats represents overall monitoring statistics
 type DashboardStats struct {
 	TotalMonitors    int     `json:"total_monitors"`
 	ActiveMonitors   int     `json:"active_monitors"`
 	UpMonitors      int     `json:"up_monitors"`
+	DegradedMonitors int    `json:"degraded_monitors"`
 	DownMonitors    int     `json:"down_monitors"`
+	MaintenanceMonitors int `json:"maintenance_monitors"`
 	OverallUptime   float64 `json:"overall_uptime"`
 	AverageResponse float64 `json:"average_response"`
 }
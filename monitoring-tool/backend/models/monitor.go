@@ -1,51 +1,169 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/jsonpath"
 )
 
 // Monitor represents an endpoint to monitor
 type Monitor struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	Name        string             `json:"name" bson:"name"`
-	URL         string             `json:"url" bson:"url"`
+	URL         string             `json:"url" bson:"url"`           // http(s) URL, host:port for tcp, hostname for dns
+	Tags        []string           `json:"tags,omitempty" bson:"tags,omitempty"` // used to target maintenance windows
+	Type        string             `json:"type" bson:"type"`         // http, tcp, dns
 	Method      string             `json:"method" bson:"method"`           // GET, POST, etc.
 	Interval    int                `json:"interval" bson:"interval"`       // seconds
 	Timeout     int                `json:"timeout" bson:"timeout"`         // seconds
+	Retries     int                `json:"retries" bson:"retries"`         // extra attempts to confirm a failure
+	RetryDelay  int                `json:"retry_delay" bson:"retry_delay"` // seconds between attempts
+	WarningThreshold  int          `json:"warning_threshold,omitempty" bson:"warning_threshold,omitempty"`   // ms, slower checks are degraded
+	CriticalThreshold int          `json:"critical_threshold,omitempty" bson:"critical_threshold,omitempty"` // ms, slower checks are down
+	RetentionDays     int          `json:"retention_days,omitempty" bson:"retention_days,omitempty"`         // days of metrics kept, 0 uses METRICS_RETENTION_DAYS
 	Status      string             `json:"status" bson:"status"`           // active, paused, error
 	IsActive    bool               `json:"is_active" bson:"is_active"`
 	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
 	LastChecked *time.Time         `json:"last_checked,omitempty" bson:"last_checked,omitempty"`
+
+	// Pause info (set while the monitor is paused)
+	PausedAt    *time.Time `json:"paused_at,omitempty" bson:"paused_at,omitempty"`
+	PausedBy    string     `json:"paused_by,omitempty" bson:"paused_by,omitempty"`
+	PauseReason string     `json:"pause_reason,omitempty" bson:"pause_reason,omitempty"`
+
+	// DNS check settings (dns monitors only)
+	DNSRecordType string   `json:"dns_record_type,omitempty" bson:"dns_record_type,omitempty"` // A, AAAA, CNAME, MX, TXT
+	DNSResolver   string   `json:"dns_resolver,omitempty" bson:"dns_resolver,omitempty"`       // host[:port], empty uses the system resolver
+	DNSExpected   []string `json:"dns_expected,omitempty" bson:"dns_expected,omitempty"`       // expected answer set, empty accepts any answer
+
+	// TLS certificate settings (https monitors only)
+	TLSExpiryWarningDays int      `json:"tls_expiry_warning_days" bson:"tls_expiry_warning_days"` // degrade when the certificate expires within this many days, 0 disables the warning
+	Certificate          *TLSInfo `json:"certificate,omitempty" bson:"certificate,omitempty"`     // certificate seen by the last check
+
+	// Request settings (http monitors only)
+	Headers     map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`
+	Body        string            `json:"body,omitempty" bson:"body,omitempty"`
+	ContentType string            `json:"content_type,omitempty" bson:"content_type,omitempty"`
+	Auth        *MonitorAuth      `json:"auth,omitempty" bson:"auth,omitempty"`
+
+	// Redirect policy (http monitors only)
+	FollowRedirects   *bool  `json:"follow_redirects,omitempty" bson:"follow_redirects,omitempty"`       // nil follows redirects
+	MaxRedirects      int    `json:"max_redirects,omitempty" bson:"max_redirects,omitempty"`             // 0 uses the default of 10
+	ExpectedFinalHost string `json:"expected_final_host,omitempty" bson:"expected_final_host,omitempty"` // fail if redirects end on another host
+
+	// Response checks (http monitors only)
+	ExpectedStatusCodes string `json:"expected_status_codes,omitempty" bson:"expected_status_codes,omitempty"` // e.g. "200-299,301,401", empty treats any status below 400 as up
+	BodyAssertions []BodyAssertion `json:"body_assertions,omitempty" bson:"body_assertions,omitempty"`
+	JSONAssertions []JSONAssertion `json:"json_assertions,omitempty" bson:"json_assertions,omitempty"`
+
+	// Alerting
+	NotificationChannelIDs []primitive.ObjectID `json:"notification_channel_ids,omitempty" bson:"notification_channel_ids,omitempty"` // empty notifies every enabled channel
+	EscalationPolicyID     *primitive.ObjectID  `json:"escalation_policy_id,omitempty" bson:"escalation_policy_id,omitempty"`         // routes incidents through the policy instead
 	
 	// Current status info (for quick dashboard display)
-	CurrentStatus     string  `json:"current_status" bson:"current_status"`         // up, down, unknown
+	CurrentStatus     string  `json:"current_status" bson:"current_status"`         // up, degraded, down, maintenance, unknown
 	CurrentResponse   int     `json:"current_response" bson:"current_response"`     // response time in ms
+	IsFlapping        bool    `json:"is_flapping" bson:"is_flapping"`               // status is oscillating, individual alerts are suppressed
 	UptimePercentage  float64 `json:"uptime_percentage" bson:"uptime_percentage"`
 }
 
+// MonitorAuth holds the credentials sent with HTTP checks.
+// Secrets are redacted whenever a monitor is encoded as JSON.
+type MonitorAuth struct {
+	Type     string `json:"type" bson:"type"` // basic, bearer
+	Username string `json:"username,omitempty" bson:"username,omitempty"`
+	Password string `json:"password,omitempty" bson:"password,omitempty"`
+	Token    string `json:"token,omitempty" bson:"token,omitempty"`
+}
+
+// BodyAssertion checks the content of an HTTP response body
+type BodyAssertion struct {
+	Type  string `json:"type" bson:"type"`   // contains, not_contains, regex
+	Value string `json:"value" bson:"value"` // substring or regular expression
+}
+
+// JSONAssertion checks a value in a JSON response body selected by a JSONPath expression
+type JSONAssertion struct {
+	Path     string `json:"path" bson:"path"`         // e.g. $.db or $.checks[0].status
+	Operator string `json:"operator" bson:"operator"` // equals, not_equals, less_than, exists
+	Value    string `json:"value" bson:"value"`       // expected value, unused for exists
+}
+
 // CreateMonitorRequest represents the request to create a new monitor
 type CreateMonitorRequest struct {
 	Name     string `json:"name" binding:"required"`
 	URL      string `json:"url" binding:"required"`
+	Type     string `json:"type"`
 	Method   string `json:"method"`
 	Interval int    `json:"interval"`
 	Timeout  int    `json:"timeout"`
+
+	Retries    int `json:"retries"`
+	RetryDelay int `json:"retry_delay"`
+
+	WarningThreshold  int `json:"warning_threshold"`
+	CriticalThreshold int `json:"critical_threshold"`
+
+	RetentionDays int `json:"retention_days"`
+
+	DNSRecordType string   `json:"dns_record_type"`
+	DNSResolver   string   `json:"dns_resolver"`
+	DNSExpected   []string `json:"dns_expected"`
+
+	TLSExpiryWarningDays *int `json:"tls_expiry_warning_days"` // nil uses the default, 0 disables the warning
+
+	Headers     map[string]string `json:"headers"`
+	Body        string            `json:"body"`
+	ContentType string            `json:"content_type"`
+	Auth        *MonitorAuth      `json:"auth"`
+
+	FollowRedirects   *bool  `json:"follow_redirects"`
+	MaxRedirects      int    `json:"max_redirects"`
+	ExpectedFinalHost string `json:"expected_final_host"`
+
+	ExpectedStatusCodes string          `json:"expected_status_codes"`
+	BodyAssertions      []BodyAssertion `json:"body_assertions"`
+	JSONAssertions      []JSONAssertion `json:"json_assertions"`
+
+	NotificationChannelIDs []primitive.ObjectID `json:"notification_channel_ids"`
+	EscalationPolicyID     *primitive.ObjectID  `json:"escalation_policy_id"`
+
+	Tags []string `json:"tags"`
 }
 
 // Validate sets default values and validates the monitor request
 func (req *CreateMonitorRequest) Validate() {
+	if req.Type == "" {
+		req.Type = "http"
+	}
 	if req.Method == "" {
 		req.Method = "GET"
 	}
+	req.Method = strings.ToUpper(req.Method)
 	if req.Interval == 0 {
 		req.Interval = 30 // 30 seconds default
 	}
 	if req.Timeout == 0 {
 		req.Timeout = 10 // 10 seconds default
 	}
+	if req.Type == "dns" && req.DNSRecordType == "" {
+		req.DNSRecordType = "A"
+	}
+	req.DNSRecordType = strings.ToUpper(req.DNSRecordType)
+	if req.TLSExpiryWarningDays == nil {
+		warningDays := 14 // 14 days default
+		req.TLSExpiryWarningDays = &warningDays
+	}
 }
 
 // ToMonitor converts a request to a Monitor model
@@ -56,9 +174,32 @@ func (req *CreateMonitorRequest) ToMonitor() *Monitor {
 	return &Monitor{
 		Name:              req.Name,
 		URL:               req.URL,
+		Type:              req.Type,
 		Method:            req.Method,
 		Interval:          req.Interval,
 		Timeout:           req.Timeout,
+		Retries:           req.Retries,
+		RetryDelay:        req.RetryDelay,
+		WarningThreshold:  req.WarningThreshold,
+		CriticalThreshold: req.CriticalThreshold,
+		RetentionDays:     req.RetentionDays,
+		DNSRecordType:     req.DNSRecordType,
+		DNSResolver:       req.DNSResolver,
+		DNSExpected:       req.DNSExpected,
+		TLSExpiryWarningDays: *req.TLSExpiryWarningDays,
+		Headers:           req.Headers,
+		Body:              req.Body,
+		ContentType:       req.ContentType,
+		Auth:              req.Auth,
+		FollowRedirects:   req.FollowRedirects,
+		MaxRedirects:      req.MaxRedirects,
+		ExpectedFinalHost: req.ExpectedFinalHost,
+		ExpectedStatusCodes: req.ExpectedStatusCodes,
+		BodyAssertions:    req.BodyAssertions,
+		JSONAssertions:    req.JSONAssertions,
+		NotificationChannelIDs: req.NotificationChannelIDs,
+		EscalationPolicyID: req.EscalationPolicyID,
+		Tags:              req.Tags,
 		Status:            "active",
 		IsActive:          true,
 		CreatedAt:         now,
@@ -68,3 +209,365 @@ func (req *CreateMonitorRequest) ToMonitor() *Monitor {
 		UptimePercentage:  100.0,
 	}
 }
+
+
+// PauseMonitorRequest represents the optional body of a pause request
+type PauseMonitorRequest struct {
+	PausedBy string `json:"paused_by"`
+	Reason   string `json:"reason"`
+}
+
+// UpdateMonitorRequest represents a partial update to an existing monitor.
+// Fields left out of the request body keep their current values.
+type UpdateMonitorRequest struct {
+	Name     *string `json:"name"`
+	URL      *string `json:"url"`
+	Type     *string `json:"type"`
+	Method   *string `json:"method"`
+	Interval *int    `json:"interval"`
+	Timeout  *int    `json:"timeout"`
+
+	Retries    *int `json:"retries"`
+	RetryDelay *int `json:"retry_delay"`
+
+	WarningThreshold  *int `json:"warning_threshold"`  // 0 disables the threshold
+	CriticalThreshold *int `json:"critical_threshold"` // 0 disables the threshold
+
+	RetentionDays *int `json:"retention_days"` // 0 goes back to METRICS_RETENTION_DAYS
+
+	DNSRecordType *string   `json:"dns_record_type"`
+	DNSResolver   *string   `json:"dns_resolver"`
+	DNSExpected   *[]string `json:"dns_expected"`
+
+	TLSExpiryWarningDays *int `json:"tls_expiry_warning_days"`
+
+	Headers     *map[string]string `json:"headers"`
+	Body        *string            `json:"body"`
+	ContentType *string            `json:"content_type"`
+	Auth        *MonitorAuth       `json:"auth"` // {"type": ""} removes authentication
+
+	FollowRedirects   *bool   `json:"follow_redirects"`
+	MaxRedirects      *int    `json:"max_redirects"`
+	ExpectedFinalHost *string `json:"expected_final_host"`
+
+	ExpectedStatusCodes *string          `json:"expected_status_codes"`
+	BodyAssertions      *[]BodyAssertion `json:"body_assertions"`
+	JSONAssertions      *[]JSONAssertion `json:"json_assertions"`
+
+	NotificationChannelIDs *[]primitive.ObjectID `json:"notification_channel_ids"` // [] notifies every enabled channel
+	EscalationPolicyID     *primitive.ObjectID   `json:"escalation_policy_id"`     // "" removes the policy
+
+	Tags *[]string `json:"tags"`
+}
+
+// ApplyTo copies the fields present in the request onto the monitor
+func (req *UpdateMonitorRequest) ApplyTo(monitor *Monitor) {
+	if req.Name != nil {
+		monitor.Name = *req.Name
+	}
+	if req.URL != nil {
+		monitor.URL = *req.URL
+	}
+	if req.Type != nil {
+		monitor.Type = *req.Type
+	}
+	if req.Method != nil {
+		monitor.Method = strings.ToUpper(*req.Method)
+	}
+	if req.Interval != nil {
+		monitor.Interval = *req.Interval
+	}
+	if req.Timeout != nil {
+		monitor.Timeout = *req.Timeout
+	}
+	if req.Retries != nil {
+		monitor.Retries = *req.Retries
+	}
+	if req.RetryDelay != nil {
+		monitor.RetryDelay = *req.RetryDelay
+	}
+	if req.WarningThreshold != nil {
+		monitor.WarningThreshold = *req.WarningThreshold
+	}
+	if req.CriticalThreshold != nil {
+		monitor.CriticalThreshold = *req.CriticalThreshold
+	}
+	if req.RetentionDays != nil {
+		monitor.RetentionDays = *req.RetentionDays
+	}
+	if req.DNSRecordType != nil {
+		monitor.DNSRecordType = strings.ToUpper(*req.DNSRecordType)
+	}
+	if monitor.Type == "dns" && monitor.DNSRecordType == "" {
+		monitor.DNSRecordType = "A" // same default as on create
+	}
+	if req.DNSResolver != nil {
+		monitor.DNSResolver = *req.DNSResolver
+	}
+	if req.DNSExpected != nil {
+		monitor.DNSExpected = *req.DNSExpected
+	}
+	if req.TLSExpiryWarningDays != nil {
+		monitor.TLSExpiryWarningDays = *req.TLSExpiryWarningDays
+	}
+	if req.Headers != nil {
+		monitor.Headers = mergeRedactedHeaders(*req.Headers, monitor.Headers)
+	}
+	if req.Body != nil {
+		monitor.Body = *req.Body
+	}
+	if req.ContentType != nil {
+		monitor.ContentType = *req.ContentType
+	}
+	if req.Auth != nil {
+		monitor.Auth = mergeRedactedAuth(req.Auth, monitor.Auth)
+	}
+	if req.FollowRedirects != nil {
+		monitor.FollowRedirects = req.FollowRedirects
+	}
+	if req.MaxRedirects != nil {
+		monitor.MaxRedirects = *req.MaxRedirects
+	}
+	if req.ExpectedFinalHost != nil {
+		monitor.ExpectedFinalHost = *req.ExpectedFinalHost
+	}
+	if req.ExpectedStatusCodes != nil {
+		monitor.ExpectedStatusCodes = *req.ExpectedStatusCodes
+	}
+	if req.BodyAssertions != nil {
+		monitor.BodyAssertions = *req.BodyAssertions
+	}
+	if req.JSONAssertions != nil {
+		monitor.JSONAssertions = *req.JSONAssertions
+	}
+	if req.NotificationChannelIDs != nil {
+		monitor.NotificationChannelIDs = *req.NotificationChannelIDs
+	}
+	if req.Tags != nil {
+		monitor.Tags = *req.Tags
+	}
+	if req.EscalationPolicyID != nil {
+		monitor.EscalationPolicyID = req.EscalationPolicyID
+		if req.EscalationPolicyID.IsZero() {
+			monitor.EscalationPolicyID = nil
+		}
+	}
+}
+
+// ErrInvalidMonitor is wrapped by all monitor validation errors
+var ErrInvalidMonitor = errors.New("invalid monitor")
+
+// allowedMethods lists the HTTP methods a monitor may use
+var allowedMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+	"OPTIONS": true,
+}
+
+// dnsRecordTypes lists the record types a dns monitor may query
+var dnsRecordTypes = map[string]bool{
+	"A":     true,
+	"AAAA":  true,
+	"CNAME": true,
+	"MX":    true,
+	"TXT":   true,
+}
+
+// Validate checks that the monitor's settings are usable for monitoring
+func (m *Monitor) Validate() error {
+	if strings.TrimSpace(m.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidMonitor)
+	}
+
+	switch m.Type {
+	case "", "http":
+		parsed, err := url.Parse(m.URL)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return fmt.Errorf("%w: URL must be an absolute http or https URL", ErrInvalidMonitor)
+		}
+		if !allowedMethods[m.Method] {
+			return fmt.Errorf("%w: unsupported method: %s", ErrInvalidMonitor, m.Method)
+		}
+		if m.Auth != nil {
+			if err := m.Auth.Validate(); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidMonitor, err)
+			}
+		}
+		if m.MaxRedirects < 0 {
+			return fmt.Errorf("%w: max_redirects cannot be negative", ErrInvalidMonitor)
+		}
+		if m.ExpectedStatusCodes != "" {
+			if _, err := ParseStatusCodes(m.ExpectedStatusCodes); err != nil {
+				return fmt.Errorf("%w: expected_status_codes: %v", ErrInvalidMonitor, err)
+			}
+		}
+		for _, assertion := range m.BodyAssertions {
+			if err := assertion.Validate(); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidMonitor, err)
+			}
+		}
+		for _, assertion := range m.JSONAssertions {
+			if err := assertion.Validate(); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidMonitor, err)
+			}
+		}
+	case "tcp":
+		if _, err := m.TCPAddress(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidMonitor, err)
+		}
+	case "dns":
+		if m.URL == "" || strings.ContainsAny(m.URL, "/: ") {
+			return fmt.Errorf("%w: URL must be a bare hostname for dns monitors", ErrInvalidMonitor)
+		}
+		if !dnsRecordTypes[m.DNSRecordType] {
+			return fmt.Errorf("%w: unsupported DNS record type: %s", ErrInvalidMonitor, m.DNSRecordType)
+		}
+		if m.DNSResolver != "" {
+			if _, err := m.DNSResolverAddress(); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidMonitor, err)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unsupported monitor type: %s", ErrInvalidMonitor, m.Type)
+	}
+
+	if m.Interval < 1 {
+		return fmt.Errorf("%w: interval must be at least 1 second", ErrInvalidMonitor)
+	}
+	if m.Timeout < 1 {
+		return fmt.Errorf("%w: timeout must be at least 1 second", ErrInvalidMonitor)
+	}
+	if m.Retries < 0 || m.Retries > 10 {
+		return fmt.Errorf("%w: retries must be between 0 and 10", ErrInvalidMonitor)
+	}
+	if m.RetryDelay < 0 {
+		return fmt.Errorf("%w: retry_delay cannot be negative", ErrInvalidMonitor)
+	}
+	for _, tag := range m.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("%w: tags cannot be empty", ErrInvalidMonitor)
+		}
+	}
+	if m.WarningThreshold < 0 || m.CriticalThreshold < 0 {
+		return fmt.Errorf("%w: response time thresholds cannot be negative", ErrInvalidMonitor)
+	}
+	if m.WarningThreshold > 0 && m.CriticalThreshold > 0 && m.WarningThreshold >= m.CriticalThreshold {
+		return fmt.Errorf("%w: warning_threshold must be lower than critical_threshold", ErrInvalidMonitor)
+	}
+	if m.TLSExpiryWarningDays < 0 {
+		return fmt.Errorf("%w: tls_expiry_warning_days cannot be negative", ErrInvalidMonitor)
+	}
+	if m.RetentionDays < 0 || m.RetentionDays > MaxRetentionDays {
+		return fmt.Errorf("%w: retention_days must be between 0 and %d", ErrInvalidMonitor, MaxRetentionDays)
+	}
+
+	return nil
+}
+
+// MaxRetentionDays bounds how long a monitor may keep its metrics
+const MaxRetentionDays = 3650
+
+// MetricsRetention returns how long the monitor's metrics are kept, falling
+// back to defaultRetention when the monitor has no override
+func (m *Monitor) MetricsRetention(defaultRetention time.Duration) time.Duration {
+	if m.RetentionDays > 0 {
+		return time.Duration(m.RetentionDays) * 24 * time.Hour
+	}
+	return defaultRetention
+}
+
+// TCPAddress returns the host:port dialled by a tcp monitor.
+// The URL may be given as "host:port" or "tcp://host:port".
+func (m *Monitor) TCPAddress() (string, error) {
+	address := strings.TrimPrefix(m.URL, "tcp://")
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("address must be in host:port form: %s", m.URL)
+	}
+	if host == "" {
+		return "", fmt.Errorf("address is missing a host: %s", m.URL)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("invalid port: %s", port)
+	}
+
+	return address, nil
+}
+
+// DNSResolverAddress returns the host:port of the resolver used by a dns
+// monitor, defaulting the port to 53
+func (m *Monitor) DNSResolverAddress() (string, error) {
+	host, port, err := net.SplitHostPort(m.DNSResolver)
+	if err != nil {
+		// No port given
+		host, port = m.DNSResolver, "53"
+	}
+	if host == "" || strings.ContainsAny(host, "/ ") {
+		return "", fmt.Errorf("invalid DNS resolver: %s", m.DNSResolver)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("invalid DNS resolver port: %s", port)
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// Validate checks that a body assertion is well formed
+func (a BodyAssertion) Validate() error {
+	if a.Value == "" {
+		return fmt.Errorf("body assertion %q requires a value", a.Type)
+	}
+
+	switch a.Type {
+	case "contains", "not_contains":
+		return nil
+	case "regex":
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("invalid body assertion regex %q: %v", a.Value, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported body assertion type: %s", a.Type)
+	}
+}
+
+// Validate checks that a JSON assertion is well formed
+func (a JSONAssertion) Validate() error {
+	if _, err := jsonpath.Compile(a.Path); err != nil {
+		return err
+	}
+
+	switch a.Operator {
+	case "exists", "equals", "not_equals":
+		return nil
+	case "less_than":
+		if _, err := strconv.ParseFloat(a.Value, 64); err != nil {
+			return fmt.Errorf("JSON assertion %s less_than requires a numeric value", a.Path)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported JSON assertion operator: %s", a.Operator)
+	}
+}
+
+// Validate checks that the credentials match the authentication type
+func (a *MonitorAuth) Validate() error {
+	switch a.Type {
+	case "basic":
+		if a.Username == "" {
+			return fmt.Errorf("basic auth requires a username")
+		}
+	case "bearer":
+		if a.Token == "" {
+			return fmt.Errorf("bearer auth requires a token")
+		}
+	default:
+		return fmt.Errorf("unsupported auth type: %s", a.Type)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package models
+
+import "testing"
+
+func TestUpdateMonitorRequestDNSRecordType(t *testing.T) {
+	dns, http := "dns", "http"
+	hostname := "example.com"
+	mx, empty := "mx", ""
+
+	tests := []struct {
+		name string
+		req  UpdateMonitorRequest
+		want string
+	}{
+		{"switch to dns defaults to A", UpdateMonitorRequest{Type: &dns, URL: &hostname}, "A"},
+		{"switch to dns keeps the record type sent", UpdateMonitorRequest{Type: &dns, URL: &hostname, DNSRecordType: &mx}, "MX"},
+		{"empty record type defaults to A", UpdateMonitorRequest{Type: &dns, URL: &hostname, DNSRecordType: &empty}, "A"},
+		{"other types get none", UpdateMonitorRequest{Type: &http}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := CreateMonitorRequest{Name: "Site", URL: "https://example.com"}
+			monitor := req.ToMonitor()
+
+			tt.req.ApplyTo(monitor)
+			if monitor.DNSRecordType != tt.want {
+				t.Fatalf("DNSRecordType = %q, want %q", monitor.DNSRecordType, tt.want)
+			}
+			if err := monitor.Validate(); err != nil {
+				t.Fatalf("Validate failed: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationChannel is a destination that receives monitor alerts
+type NotificationChannel struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name      string             `json:"name" bson:"name"`
+	Type      string             `json:"type" bson:"type"` // webhook, email, slack, discord, teams, mattermost
+	Enabled   bool               `json:"enabled" bson:"enabled"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+
+	// Webhook settings (url is also the incoming webhook of chat channels)
+	URL      string            `json:"url,omitempty" bson:"url,omitempty"`
+	Secret   string            `json:"secret,omitempty" bson:"secret,omitempty"` // HMAC-SHA256 signing secret
+	Headers  map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`
+	Template string            `json:"template,omitempty" bson:"template,omitempty"` // text/template for the JSON body, empty sends the event as is
+
+	// Email settings
+	Recipients []string `json:"recipients,omitempty" bson:"recipients,omitempty"`
+}
+
+// CreateChannelRequest represents the request to create a notification channel
+type CreateChannelRequest struct {
+	Name     string            `json:"name" binding:"required"`
+	Type     string            `json:"type"`
+	Enabled  *bool             `json:"enabled"`
+	URL      string            `json:"url"`
+	Secret   string            `json:"secret"`
+	Headers  map[string]string `json:"headers"`
+	Template string            `json:"template"`
+
+	Recipients []string `json:"recipients"`
+}
+
+// ErrInvalidChannel is wrapped by all notification channel validation errors
+var ErrInvalidChannel = errors.New("invalid notification channel")
+
+// ToChannel converts a request to a NotificationChannel model
+func (req *CreateChannelRequest) ToChannel() *NotificationChannel {
+	if req.Type == "" {
+		req.Type = "webhook"
+	}
+	enabled := req.Enabled == nil || *req.Enabled
+	now := time.Now()
+
+	return &NotificationChannel{
+		Name:       req.Name,
+		Type:       req.Type,
+		Enabled:    enabled,
+		URL:        req.URL,
+		Secret:     req.Secret,
+		Headers:    req.Headers,
+		Template:   req.Template,
+		Recipients: req.Recipients,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// Validate checks that the channel has the settings its type needs
+func (ch *NotificationChannel) Validate() error {
+	if strings.TrimSpace(ch.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidChannel)
+	}
+
+	switch ch.Type {
+	case "webhook", "slack", "discord", "teams", "mattermost":
+		parsed, err := url.Parse(ch.URL)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidChannel)
+		}
+		if ch.Template != "" && ch.Type != "webhook" {
+			return fmt.Errorf("%w: templates are only supported by webhook channels", ErrInvalidChannel)
+		}
+		if ch.Template != "" {
+			if _, err := template.New("webhook").Funcs(TemplateFuncs).Parse(ch.Template); err != nil {
+				return fmt.Errorf("%w: invalid template: %v", ErrInvalidChannel, err)
+			}
+		}
+	case "email":
+		if len(ch.Recipients) == 0 {
+			return fmt.Errorf("%w: at least one recipient is required", ErrInvalidChannel)
+		}
+		for _, recipient := range ch.Recipients {
+			if _, err := mail.ParseAddress(recipient); err != nil {
+				return fmt.Errorf("%w: invalid recipient %q", ErrInvalidChannel, recipient)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unsupported channel type: %s", ErrInvalidChannel, ch.Type)
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the channel with its signing secret, sensitive header
+// values and, for chat channels, the incoming webhook URL redacted, since
+// the URL alone is enough to post to the channel
+func (ch NotificationChannel) MarshalJSON() ([]byte, error) {
+	type channelJSON NotificationChannel
+
+	if ch.Secret != "" {
+		ch.Secret = RedactedValue
+	}
+	ch.Headers = redactHeaders(ch.Headers)
+	switch ch.Type {
+	case "slack", "discord", "teams", "mattermost":
+		ch.URL = RedactedValue
+	}
+
+	return json.Marshal(channelJSON(ch))
+}
+
+// TemplateFuncs are available to webhook templates. {{json .Error}} renders
+// a value as a JSON literal, quoting and escaping strings.
+var TemplateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	},
+}
+
+// NotificationEvent describes a monitor state transition sent to channels
+type NotificationEvent struct {
+	Type             string    `json:"type"` // monitor_down, monitor_up, monitor_degraded, monitor_flapping_started, monitor_flapping_stopped, test
+	MonitorID        string    `json:"monitor_id"`
+	MonitorName      string    `json:"monitor_name"`
+	URL              string    `json:"url"`
+	Status           string    `json:"status"`
+	PreviousStatus   string    `json:"previous_status"`
+	StatusCode       int       `json:"status_code"`
+	ResponseTime     int64     `json:"response_time"`
+	Error            string    `json:"error,omitempty"`
+	IncidentID       string    `json:"incident_id,omitempty"`
+	IncidentDuration int64     `json:"incident_duration,omitempty"` // seconds, set on recovery
+	EscalationLevel  int       `json:"escalation_level,omitempty"`  // escalation step that sent the event, starting at 1
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// NotificationDelivery records the delivery of one event to one channel
+type NotificationDelivery struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ChannelID   primitive.ObjectID `json:"channel_id" bson:"channel_id"`
+	ChannelName string             `json:"channel_name" bson:"channel_name"`
+	EventType   string             `json:"event_type" bson:"event_type"`
+	MonitorID   string             `json:"monitor_id,omitempty" bson:"monitor_id,omitempty"`
+	Status      string             `json:"status" bson:"status"` // delivered, failed
+	Attempts    []DeliveryAttempt  `json:"attempts" bson:"attempts"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	CompletedAt time.Time          `json:"completed_at" bson:"completed_at"`
+}
+
+// DeliveryAttempt is the outcome of a single attempt to send an event
+type DeliveryAttempt struct {
+	Attempt    int       `json:"attempt" bson:"attempt"`
+	StatusCode int       `json:"status_code,omitempty" bson:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty" bson:"error,omitempty"`
+	SentAt     time.Time `json:"sent_at" bson:"sent_at"`
+}
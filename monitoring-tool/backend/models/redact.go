@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// RedactedValue replaces secrets in API responses
+const RedactedValue = "********"
+
+// sensitiveHeaderWords mark header names whose values are treated as secrets
+var sensitiveHeaderWords = []string{"authorization", "cookie", "token", "secret", "password", "api-key", "apikey"}
+
+// MarshalJSON encodes the monitor with credentials and sensitive header
+// values redacted so that secrets are never echoed back by the API
+func (m Monitor) MarshalJSON() ([]byte, error) {
+	type monitorJSON Monitor
+	return json.Marshal(monitorJSON(m.Redacted()))
+}
+
+// Redacted returns a copy of the monitor with its secrets replaced by RedactedValue
+func (m Monitor) Redacted() Monitor {
+	if m.Auth != nil {
+		auth := *m.Auth
+		if auth.Password != "" {
+			auth.Password = RedactedValue
+		}
+		if auth.Token != "" {
+			auth.Token = RedactedValue
+		}
+		m.Auth = &auth
+	}
+
+	m.Headers = redactHeaders(m.Headers)
+
+	return m
+}
+
+// redactHeaders returns a copy of headers with sensitive values redacted
+func redactHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return headers
+	}
+
+	redacted := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if isSensitiveHeader(name) {
+			value = RedactedValue
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// isSensitiveHeader reports whether a header value should be redacted
+func isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range sensitiveHeaderWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeRedactedHeaders keeps the stored value of any header that a client
+// sent back still redacted
+func mergeRedactedHeaders(headers, current map[string]string) map[string]string {
+	merged := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if value == RedactedValue {
+			value = current[name]
+		}
+		merged[name] = value
+	}
+	return merged
+}
+
+// mergeRedactedAuth keeps stored secrets that a client sent back still
+// redacted. An empty type removes authentication.
+func mergeRedactedAuth(auth, current *MonitorAuth) *MonitorAuth {
+	if auth.Type == "" {
+		return nil
+	}
+
+	merged := *auth
+	if current != nil {
+		if merged.Password == RedactedValue {
+			merged.Password = current.Password
+		}
+		if merged.Token == RedactedValue {
+			merged.Token = current.Token
+		}
+	}
+	return &merged
+}
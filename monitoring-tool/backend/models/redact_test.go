@@ -0,0 +1,161 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// secrets are the values stored on the test monitors that must never be encoded
+var secrets = []string{"s3cret-pass", "bearer-t0ken", "k3y-value", "sess10n", "x-t0ken-value", "db-passw0rd"}
+
+func monitorWithSecrets(authType string) Monitor {
+	monitor := Monitor{
+		Name:   "API",
+		URL:    "https://api.example.com",
+		Type:   "http",
+		Method: "GET",
+		Headers: map[string]string{
+			"X-API-Key":        "k3y-value",
+			"Cookie":           "sess10n",
+			"X-Auth-Token":     "x-t0ken-value",
+			"X-Db-Password":    "db-passw0rd",
+			"Accept":           "application/json",
+			"X-Request-Source": "monitor",
+		},
+	}
+	switch authType {
+	case "basic":
+		monitor.Auth = &MonitorAuth{Type: "basic", Username: "alice", Password: "s3cret-pass"}
+	case "bearer":
+		monitor.Auth = &MonitorAuth{Type: "bearer", Token: "bearer-t0ken"}
+	}
+	return monitor
+}
+
+func TestMonitorMarshalJSONRedactsSecrets(t *testing.T) {
+	for _, authType := range []string{"basic", "bearer", ""} {
+		t.Run("auth "+authType, func(t *testing.T) {
+			monitor := monitorWithSecrets(authType)
+
+			// Values, pointers and slices all go through MarshalJSON
+			for name, value := range map[string]interface{}{
+				"value":   monitor,
+				"pointer": &monitor,
+				"slice":   []Monitor{monitor},
+				"map":     map[string]interface{}{"data": monitor},
+			} {
+				encoded, err := json.Marshal(value)
+				if err != nil {
+					t.Fatalf("failed to encode %s: %v", name, err)
+				}
+				for _, secret := range secrets {
+					if strings.Contains(string(encoded), secret) {
+						t.Errorf("%s encoding exposes %q: %s", name, secret, encoded)
+					}
+				}
+			}
+
+			encoded, err := json.Marshal(monitor)
+			if err != nil {
+				t.Fatalf("failed to encode monitor: %v", err)
+			}
+			var decoded Monitor
+			if err := json.Unmarshal(encoded, &decoded); err != nil {
+				t.Fatalf("failed to decode monitor: %v", err)
+			}
+			for name, want := range map[string]string{
+				"X-API-Key":        RedactedValue,
+				"Cookie":           RedactedValue,
+				"X-Auth-Token":     RedactedValue,
+				"X-Db-Password":    RedactedValue,
+				"Accept":           "application/json",
+				"X-Request-Source": "monitor",
+			} {
+				if got := decoded.Headers[name]; got != want {
+					t.Errorf("header %s = %q, want %q", name, got, want)
+				}
+			}
+			if authType == "basic" && (decoded.Auth.Username != "alice" || decoded.Auth.Password != RedactedValue) {
+				t.Errorf("basic auth = %+v, want the username and a redacted password", decoded.Auth)
+			}
+			if authType == "bearer" && decoded.Auth.Token != RedactedValue {
+				t.Errorf("bearer auth = %+v, want a redacted token", decoded.Auth)
+			}
+			if authType == "" && decoded.Auth != nil {
+				t.Errorf("auth = %+v, want none", decoded.Auth)
+			}
+
+			// Encoding must not change the stored monitor
+			if fresh := monitorWithSecrets(authType); !reflect.DeepEqual(monitor, fresh) {
+				t.Errorf("encoding modified the monitor: %+v", monitor)
+			}
+		})
+	}
+}
+
+func TestUpdateMonitorRequestKeepsRedactedSecrets(t *testing.T) {
+	for _, authType := range []string{"basic", "bearer"} {
+		t.Run(authType, func(t *testing.T) {
+			stored := monitorWithSecrets(authType)
+
+			// A client that edits the name and sends back everything it read
+			encoded, err := json.Marshal(stored)
+			if err != nil {
+				t.Fatalf("failed to encode monitor: %v", err)
+			}
+			var req UpdateMonitorRequest
+			if err := json.Unmarshal(encoded, &req); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+			name := "Renamed API"
+			req.Name = &name
+
+			updated := monitorWithSecrets(authType)
+			req.ApplyTo(&updated)
+
+			if updated.Name != name {
+				t.Errorf("name = %q, want %q", updated.Name, name)
+			}
+			for header, value := range stored.Headers {
+				if updated.Headers[header] != value {
+					t.Errorf("header %s = %q, want the stored %q", header, updated.Headers[header], value)
+				}
+			}
+			if *updated.Auth != *stored.Auth {
+				t.Errorf("auth = %+v, want the stored %+v", updated.Auth, stored.Auth)
+			}
+		})
+	}
+}
+
+func TestUpdateMonitorRequestReplacesSecrets(t *testing.T) {
+	monitor := monitorWithSecrets("basic")
+	req := UpdateMonitorRequest{
+		Headers: &map[string]string{"X-API-Key": "new-key", "Cookie": RedactedValue, "Authorization": RedactedValue},
+		Auth:    &MonitorAuth{Type: "basic", Username: "bob", Password: "new-pass"},
+	}
+	req.ApplyTo(&monitor)
+
+	// Sent headers replace the stored ones; a redacted header that was not
+	// stored has no secret to keep
+	want := map[string]string{"X-API-Key": "new-key", "Cookie": "sess10n", "Authorization": ""}
+	if len(monitor.Headers) != len(want) {
+		t.Errorf("headers = %v, want %v", monitor.Headers, want)
+	}
+	for name, value := range want {
+		if monitor.Headers[name] != value {
+			t.Errorf("header %s = %q, want %q", name, monitor.Headers[name], value)
+		}
+	}
+	if monitor.Auth == nil || monitor.Auth.Username != "bob" || monitor.Auth.Password != "new-pass" {
+		t.Errorf("auth = %+v, want the new credentials", monitor.Auth)
+	}
+
+	// An empty type removes authentication
+	(&UpdateMonitorRequest{Auth: &MonitorAuth{}}).ApplyTo(&monitor)
+	if monitor.Auth != nil {
+		t.Errorf("auth after removal = %+v, want none", monitor.Auth)
+	}
+}
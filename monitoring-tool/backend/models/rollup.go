@@ -0,0 +1,88 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Rollup resolutions, finest first
+const (
+	ResolutionMinute = "1m"
+	ResolutionHour   = "1h"
+	ResolutionDay    = "1d"
+)
+
+// Resolutions lists every rollup resolution, finest first
+var Resolutions = []string{ResolutionMinute, ResolutionHour, ResolutionDay}
+
+// ResolutionDuration returns the bucket size of a rollup resolution, or 0
+// when the resolution is unknown
+func ResolutionDuration(resolution string) time.Duration {
+	switch resolution {
+	case ResolutionMinute:
+		return time.Minute
+	case ResolutionHour:
+		return time.Hour
+	case ResolutionDay:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+// MetricRollup aggregates the checks of a monitor within one time bucket.
+// Checks made during a maintenance window are counted separately and are
+// not part of the up, degraded and down counts.
+type MetricRollup struct {
+	ID               primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	MonitorID        primitive.ObjectID `json:"monitor_id" bson:"monitor_id"`
+	Resolution       string             `json:"resolution" bson:"resolution"`
+	BucketStart      time.Time          `json:"bucket_start" bson:"bucket_start"`
+	Count            int                `json:"count" bson:"count"`
+	UpCount          int                `json:"up_count" bson:"up_count"`
+	DegradedCount    int                `json:"degraded_count" bson:"degraded_count"`
+	DownCount        int                `json:"down_count" bson:"down_count"`
+	MaintenanceCount int                `json:"maintenance_count" bson:"maintenance_count"`
+	MinResponse      int64              `json:"min_response" bson:"min_response"` // milliseconds
+	AvgResponse      float64            `json:"avg_response" bson:"avg_response"`
+	MaxResponse      int64              `json:"max_response" bson:"max_response"`
+	P95Response      int64              `json:"p95_response" bson:"p95_response"`
+	ExpiresAt        time.Time          `json:"-" bson:"expires_at"` // removed by the TTL index at this time
+}
+
+// NewMetricRollup aggregates the metrics of one bucket. metrics must not be empty.
+func NewMetricRollup(monitorID primitive.ObjectID, resolution string, bucketStart time.Time, metrics []Metric) MetricRollup {
+	rollup := MetricRollup{
+		MonitorID:   monitorID,
+		Resolution:  resolution,
+		BucketStart: bucketStart,
+		Count:       len(metrics),
+	}
+
+	latencies := make([]int64, 0, len(metrics))
+	var total int64
+	for _, metric := range metrics {
+		switch {
+		case metric.InMaintenance:
+			rollup.MaintenanceCount++
+		case metric.Status == "up":
+			rollup.UpCount++
+		case metric.Status == "degraded":
+			rollup.DegradedCount++
+		default:
+			rollup.DownCount++
+		}
+		latencies = append(latencies, metric.ResponseTime)
+		total += metric.ResponseTime
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	rollup.MinResponse = latencies[0]
+	rollup.MaxResponse = latencies[len(latencies)-1]
+	rollup.AvgResponse = float64(total) / float64(len(latencies))
+	// Nearest-rank percentile
+	rollup.P95Response = latencies[(len(latencies)*95+99)/100-1]
+
+	return rollup
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNewMetricRollup(t *testing.T) {
+	monitorID := primitive.NewObjectID()
+	bucketStart := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// 20 checks answering in 1..20ms: 14 up, 2 degraded, 2 down (one of
+	// them with an unknown status) and 2 in maintenance, one of them down
+	var metrics []Metric
+	for i := 1; i <= 20; i++ {
+		metric := Metric{Status: "up", ResponseTime: int64(i)}
+		switch i {
+		case 3, 4:
+			metric.Status = "degraded"
+		case 5:
+			metric.Status = "down"
+		case 6:
+			metric.Status = "error"
+		case 7:
+			metric.InMaintenance = true
+		case 8:
+			metric.Status = "down"
+			metric.InMaintenance = true
+		}
+		metrics = append(metrics, metric)
+	}
+
+	rollup := NewMetricRollup(monitorID, ResolutionMinute, bucketStart, metrics)
+	want := MetricRollup{
+		MonitorID:        monitorID,
+		Resolution:       ResolutionMinute,
+		BucketStart:      bucketStart,
+		Count:            20,
+		UpCount:          14,
+		DegradedCount:    2,
+		DownCount:        2,
+		MaintenanceCount: 2,
+		MinResponse:      1,
+		AvgResponse:      10.5,
+		MaxResponse:      20,
+		P95Response:      19,
+	}
+	if rollup != want {
+		t.Fatalf("rollup = %+v, want %+v", rollup, want)
+	}
+}
+
+func TestNewMetricRollupP95(t *testing.T) {
+	tests := []struct {
+		name      string
+		latencies []int64
+		want      int64
+	}{
+		{"single check", []int64{42}, 42},
+		{"unsorted", []int64{30, 10, 20}, 30},
+		{"20 checks", []int64{20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, 19},
+		// Nearest rank of 95% of 21 is the 20th
+		{"21 checks", []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21}, 20},
+		{"100 checks with outliers", append(make([]int64, 95), 500, 600, 700, 800, 900), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := make([]Metric, len(tt.latencies))
+			for i, latency := range tt.latencies {
+				metrics[i] = Metric{Status: "up", ResponseTime: latency}
+			}
+			if got := NewMetricRollup(primitive.NewObjectID(), ResolutionHour, time.Time{}, metrics).P95Response; got != tt.want {
+				t.Errorf("P95Response = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
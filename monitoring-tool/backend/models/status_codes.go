@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StatusCodeRange is an inclusive range of HTTP status codes
+type StatusCodeRange struct {
+	Min int
+	Max int
+}
+
+// StatusCodeSet is a parsed expected-status-code expression such as "2xx,301,401"
+type StatusCodeSet []StatusCodeRange
+
+// ParseStatusCodes parses a comma separated list of status codes, ranges
+// and classes such as 2xx
+func ParseStatusCodes(expr string) (StatusCodeSet, error) {
+	var set StatusCodeSet
+
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		if class, ok := parseStatusClass(part); ok {
+			set = append(set, StatusCodeRange{Min: class * 100, Max: class*100 + 99})
+			continue
+		}
+
+		low, high, isRange := strings.Cut(part, "-")
+		min, err := parseStatusCode(low)
+		if err != nil {
+			return nil, err
+		}
+		max := min
+		if isRange {
+			if max, err = parseStatusCode(high); err != nil {
+				return nil, err
+			}
+			if max < min {
+				return nil, fmt.Errorf("invalid status code range %q", part)
+			}
+		}
+
+		set = append(set, StatusCodeRange{Min: min, Max: max})
+	}
+
+	if len(set) == 0 {
+		return nil, fmt.Errorf("status code expression %q is empty", expr)
+	}
+
+	return set, nil
+}
+
+// Contains reports whether the status code is accepted by the set
+func (s StatusCodeSet) Contains(code int) bool {
+	for _, r := range s {
+		if code >= r.Min && code <= r.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// parseStatusCode parses a single three-digit HTTP status code
+func parseStatusCode(value string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("invalid status code %q", value)
+	}
+	return code, nil
+}
+
+// parseStatusClass parses a status class such as 2xx into its first digit
+func parseStatusClass(value string) (int, bool) {
+	if len(value) != 3 || value[0] < '1' || value[0] > '5' || !strings.EqualFold(value[1:], "xx") {
+		return 0, false
+	}
+	return int(value[0] - '0'), true
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStatusCodes(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    StatusCodeSet
+		wantErr string
+	}{
+		{expr: "200", want: StatusCodeSet{{200, 200}}},
+		{expr: "200-299", want: StatusCodeSet{{200, 299}}},
+		{expr: "200-200", want: StatusCodeSet{{200, 200}}},
+		{expr: "2xx", want: StatusCodeSet{{200, 299}}},
+		{expr: "5XX", want: StatusCodeSet{{500, 599}}},
+		{expr: "1xx,3xx", want: StatusCodeSet{{100, 199}, {300, 399}}},
+		{expr: "200-299,301,401", want: StatusCodeSet{{200, 299}, {301, 301}, {401, 401}}},
+		{expr: "2xx,404", want: StatusCodeSet{{200, 299}, {404, 404}}},
+		{expr: " 200 - 204 , 301 ,, ", want: StatusCodeSet{{200, 204}, {301, 301}}},
+		{expr: "\t2xx\n", want: StatusCodeSet{{200, 299}}},
+
+		{expr: "", wantErr: "is empty"},
+		{expr: " , ", wantErr: "is empty"},
+		{expr: "299-200", wantErr: "invalid status code range"},
+		{expr: "99", wantErr: "invalid status code"},
+		{expr: "600", wantErr: "invalid status code"},
+		{expr: "200-600", wantErr: "invalid status code"},
+		{expr: "0-299", wantErr: "invalid status code"},
+		{expr: "6xx", wantErr: "invalid status code"},
+		{expr: "0xx", wantErr: "invalid status code"},
+		{expr: "2x", wantErr: "invalid status code"},
+		{expr: "200-", wantErr: "invalid status code"},
+		{expr: "-200", wantErr: "invalid status code"},
+		{expr: "ok", wantErr: "invalid status code"},
+		{expr: "200,abc", wantErr: "invalid status code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseStatusCodes(tt.expr)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseStatusCodes(%q) = %v, %v, want error %q", tt.expr, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStatusCodes(%q) failed: %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStatusCodes(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusCodeSetContains(t *testing.T) {
+	set, err := ParseStatusCodes("2xx,301,401-403")
+	if err != nil {
+		t.Fatalf("ParseStatusCodes failed: %v", err)
+	}
+
+	for code, want := range map[int]bool{
+		199: false, 200: true, 299: true, 300: false, 301: true, 302: false,
+		400: false, 401: true, 403: true, 404: false, 500: false,
+	} {
+		if got := set.Contains(code); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
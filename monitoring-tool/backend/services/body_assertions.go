@@ -0,0 +1,58 @@
+// services/body_assertions.go
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+
+	"monitoring-tool/models"
+)
+
+// maxBodyBytes bounds how much of a response body is read for body assertions
+const maxBodyBytes = 64 * 1024
+
+// maxJSONBodyBytes bounds how much of a response body is read for JSON
+// assertions, which need the whole document
+const maxJSONBodyBytes = 1024 * 1024
+
+// readBody reads at most limit bytes of the response body and reports
+// whether the body was longer
+func readBody(resp *http.Response, limit int64) ([]byte, bool, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if int64(len(body)) > limit {
+		return body[:limit], true, err
+	}
+	return body, false, err
+}
+
+// checkBodyAssertions returns an error describing the first assertion that
+// the body does not satisfy
+func checkBodyAssertions(assertions []models.BodyAssertion, body []byte) error {
+	for _, assertion := range assertions {
+		var passed bool
+
+		switch assertion.Type {
+		case "contains":
+			passed = bytes.Contains(body, []byte(assertion.Value))
+		case "not_contains":
+			passed = !bytes.Contains(body, []byte(assertion.Value))
+		case "regex":
+			re, err := regexp.Compile(assertion.Value)
+			if err != nil {
+				return fmt.Errorf("body assertion failed: invalid regex %q: %v", assertion.Value, err)
+			}
+			passed = re.Match(body)
+		default:
+			return fmt.Errorf("body assertion failed: unsupported type %s", assertion.Type)
+		}
+
+		if !passed {
+			return fmt.Errorf("body assertion failed: %s %q", assertion.Type, assertion.Value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,308 @@
+// services/chat_formatters.go
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"monitoring-tool/models"
+)
+
+// chatField is a labelled value shown in a chat message
+type chatField struct {
+	Title string
+	Value string
+}
+
+// chatMessage is the platform independent content of a chat alert
+type chatMessage struct {
+	Title        string
+	Summary      string
+	Color        string // hex, e.g. #d93025
+	Fields       []chatField
+	Error        string
+	DashboardURL string
+	Timestamp    time.Time
+}
+
+// statusColors maps monitor statuses to the colour used for their alerts
+var statusColors = map[string]string{
+	"up":       "#2eb67d",
+	"degraded": "#ecb22e",
+	"down":     "#e01e5a",
+}
+
+// sendChat posts the event to a chat platform's incoming webhook
+func (ns *NotificationService) sendChat(channel models.NotificationChannel, event models.NotificationEvent) (int, error) {
+	message := newChatMessage(event, ns.cfg.DashboardURL)
+
+	var payload interface{}
+	switch channel.Type {
+	case "slack":
+		payload = slackPayload(message)
+	case "discord":
+		payload = discordPayload(message)
+	case "teams":
+		payload = teamsPayload(message)
+	case "mattermost":
+		payload = mattermostPayload(message)
+	default:
+		return 0, fmt.Errorf("unsupported chat channel type: %s", channel.Type)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, channel.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "RealtimeMonitor/1.0")
+	for name, value := range channel.Headers {
+		req.Header.Set(name, value)
+	}
+
+	return ns.post(req)
+}
+
+// newChatMessage builds the shared content of a chat alert from an event
+func newChatMessage(event models.NotificationEvent, dashboardURL string) chatMessage {
+	message := chatMessage{
+		Color:        statusColors[event.Status],
+		DashboardURL: dashboardURL,
+		Timestamp:    event.Timestamp,
+		Error:        event.Error,
+	}
+	if message.Color == "" {
+		message.Color = "#868e96"
+	}
+
+	switch {
+	case event.Type == "test":
+		message.Title = "Test notification"
+		message.Summary = "This channel is set up to receive monitor alerts."
+		return message
+	case event.Type == "monitor_flapping_started":
+		message.Title = fmt.Sprintf("🔁 %s is flapping", event.MonitorName)
+		message.Color = statusColors["degraded"]
+	case event.Type == "monitor_flapping_stopped":
+		message.Title = fmt.Sprintf("%s stopped flapping and is %s", event.MonitorName, event.Status)
+	case event.Status == "down":
+		message.Title = fmt.Sprintf("🔴 %s is down", event.MonitorName)
+	case event.Status == "degraded":
+		message.Title = fmt.Sprintf("🟡 %s is degraded", event.MonitorName)
+	case event.Status == "up" && event.PreviousStatus == "down":
+		message.Title = fmt.Sprintf("🟢 %s has recovered", event.MonitorName)
+	default:
+		message.Title = fmt.Sprintf("%s is %s", event.MonitorName, event.Status)
+	}
+	message.Summary = fmt.Sprintf("%s (%s)", message.Title, event.URL)
+
+	message.Fields = append(message.Fields,
+		chatField{Title: "Status", Value: strings.ToUpper(event.Status)},
+		chatField{Title: "Response time", Value: fmt.Sprintf("%dms", event.ResponseTime)},
+	)
+	if event.StatusCode != 0 {
+		message.Fields = append(message.Fields, chatField{Title: "HTTP status", Value: fmt.Sprintf("%d", event.StatusCode)})
+	}
+	if event.IncidentDuration > 0 {
+		message.Fields = append(message.Fields, chatField{Title: "Down for", Value: (time.Duration(event.IncidentDuration) * time.Second).String()})
+	}
+	message.Fields = append(message.Fields, chatField{Title: "URL", Value: event.URL})
+
+	return message
+}
+
+// slackPayload formats a message as Block Kit blocks inside a coloured attachment
+func slackPayload(message chatMessage) map[string]interface{} {
+	fields := []map[string]interface{}{}
+	for _, field := range message.Fields {
+		fields = append(fields, map[string]interface{}{
+			"type": "mrkdwn",
+			"text": fmt.Sprintf("*%s*\n%s", field.Title, field.Value),
+		})
+	}
+
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": message.Title},
+		},
+	}
+	if len(fields) > 0 {
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	} else {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": message.Summary},
+		})
+	}
+	if message.Error != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("*Error*\n```%s```", message.Error)},
+		})
+	}
+	if message.DashboardURL != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "actions",
+			"elements": []map[string]interface{}{
+				{
+					"type": "button",
+					"text": map[string]interface{}{"type": "plain_text", "text": "Open dashboard"},
+					"url":  message.DashboardURL,
+				},
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"text": message.Summary,
+		"attachments": []map[string]interface{}{
+			{"color": message.Color, "blocks": blocks},
+		},
+	}
+}
+
+// discordPayload formats a message as a Discord embed
+func discordPayload(message chatMessage) map[string]interface{} {
+	fields := []map[string]interface{}{}
+	for _, field := range message.Fields {
+		fields = append(fields, map[string]interface{}{
+			"name":   field.Title,
+			"value":  field.Value,
+			"inline": field.Title != "URL",
+		})
+	}
+
+	embed := map[string]interface{}{
+		"title":     message.Title,
+		"color":     colorValue(message.Color),
+		"fields":    fields,
+		"timestamp": message.Timestamp.Format(time.RFC3339),
+	}
+	if message.Error != "" {
+		embed["description"] = fmt.Sprintf("```%s```", message.Error)
+	} else if len(fields) == 0 {
+		embed["description"] = message.Summary
+	}
+	if message.DashboardURL != "" {
+		embed["url"] = message.DashboardURL
+	}
+
+	return map[string]interface{}{
+		"username": "Realtime Monitor",
+		"embeds":   []map[string]interface{}{embed},
+	}
+}
+
+// teamsPayload formats a message as an Adaptive Card for Teams workflows
+func teamsPayload(message chatMessage) map[string]interface{} {
+	facts := []map[string]interface{}{}
+	for _, field := range message.Fields {
+		facts = append(facts, map[string]interface{}{"title": field.Title, "value": field.Value})
+	}
+
+	body := []map[string]interface{}{
+		{
+			"type":   "TextBlock",
+			"text":   message.Title,
+			"size":   "Large",
+			"weight": "Bolder",
+			"color":  teamsColor(message.Color),
+			"wrap":   true,
+		},
+	}
+	if len(facts) > 0 {
+		body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+	} else {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": message.Summary, "wrap": true})
+	}
+	if message.Error != "" {
+		body = append(body, map[string]interface{}{
+			"type":     "TextBlock",
+			"text":     message.Error,
+			"fontType": "Monospace",
+			"wrap":     true,
+		})
+	}
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if message.DashboardURL != "" {
+		card["actions"] = []map[string]interface{}{
+			{"type": "Action.OpenUrl", "title": "Open dashboard", "url": message.DashboardURL},
+		}
+	}
+
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	}
+}
+
+// mattermostPayload formats a message as a Mattermost message attachment
+func mattermostPayload(message chatMessage) map[string]interface{} {
+	fields := []map[string]interface{}{}
+	for _, field := range message.Fields {
+		fields = append(fields, map[string]interface{}{
+			"title": field.Title,
+			"value": field.Value,
+			"short": field.Title != "URL",
+		})
+	}
+
+	attachment := map[string]interface{}{
+		"fallback": message.Summary,
+		"color":    message.Color,
+		"title":    message.Title,
+		"fields":   fields,
+	}
+	if message.Error != "" {
+		attachment["text"] = fmt.Sprintf("```\n%s\n```", message.Error)
+	} else if len(fields) == 0 {
+		attachment["text"] = message.Summary
+	}
+	if message.DashboardURL != "" {
+		attachment["title_link"] = message.DashboardURL
+	}
+
+	return map[string]interface{}{
+		"username":    "Realtime Monitor",
+		"attachments": []map[string]interface{}{attachment},
+	}
+}
+
+// colorValue converts a #rrggbb colour to the integer form Discord expects
+func colorValue(hex string) int {
+	var value int
+	fmt.Sscanf(strings.TrimPrefix(hex, "#"), "%x", &value)
+	return value
+}
+
+// teamsColor maps a status colour to the closest Adaptive Card colour name
+func teamsColor(hex string) string {
+	switch hex {
+	case statusColors["down"]:
+		return "Attention"
+	case statusColors["degraded"]:
+		return "Warning"
+	case statusColors["up"]:
+		return "Good"
+	default:
+		return "Default"
+	}
+}
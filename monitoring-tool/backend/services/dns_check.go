@@ -0,0 +1,178 @@
+// services/dns_check.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"monitoring-tool/models"
+)
+
+// checkDNS resolves the monitor's hostname and compares the answer set
+// against the expected values
+func (ms *MonitorService) checkDNS(monitor models.Monitor) models.Metric {
+	resolver, err := dnsResolver(monitor)
+	if err != nil {
+		return models.Metric{Status: "down", Error: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(monitor.Timeout)*time.Second)
+	defer cancel()
+
+	startTime := time.Now()
+	answers, err := lookupRecords(ctx, resolver, monitor.DNSRecordType, monitor.URL)
+	responseTime := time.Since(startTime).Milliseconds()
+
+	if err != nil {
+		return models.Metric{Status: "down", ResponseTime: responseTime, Error: err.Error()}
+	}
+
+	metric := models.Metric{Status: "up", ResponseTime: responseTime, DNSAnswers: answers}
+
+	if len(answers) == 0 {
+		metric.Status = "down"
+		metric.Error = fmt.Sprintf("no %s records found for %s", monitor.DNSRecordType, monitor.URL)
+	} else if mismatch := compareDNSAnswers(monitor.DNSRecordType, monitor.DNSExpected, answers); mismatch != "" {
+		metric.Status = "down"
+		metric.Error = mismatch
+	}
+
+	return metric
+}
+
+// dnsResolver returns the resolver for a monitor, either the system resolver
+// or one that sends every query to the configured resolver address
+func dnsResolver(monitor models.Monitor) (*net.Resolver, error) {
+	if monitor.DNSResolver == "" {
+		return net.DefaultResolver, nil
+	}
+
+	address, err := monitor.DNSResolverAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, address)
+		},
+	}, nil
+}
+
+// lookupRecords queries a single record type and returns the normalized answers
+func lookupRecords(ctx context.Context, resolver *net.Resolver, recordType, host string) ([]string, error) {
+	var answers []string
+
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, cname)
+	case "MX":
+		records, err := resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range records {
+			answers = append(answers, mx.Host)
+		}
+	case "TXT":
+		records, err := resolver.LookupTXT(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, records...)
+	default:
+		return nil, fmt.Errorf("unsupported DNS record type: %s", recordType)
+	}
+
+	for i, answer := range answers {
+		answers[i] = normalizeDNSAnswer(recordType, answer)
+	}
+	sort.Strings(answers)
+
+	return answers, nil
+}
+
+// compareDNSAnswers returns a description of the differences between the
+// expected and actual answer sets, or "" when they match
+func compareDNSAnswers(recordType string, expected, answers []string) string {
+	if len(expected) == 0 {
+		return ""
+	}
+
+	actual := make(map[string]bool, len(answers))
+	for _, answer := range answers {
+		actual[answer] = true
+	}
+
+	wanted := make(map[string]bool, len(expected))
+	var missing []string
+	for _, value := range expected {
+		value = normalizeDNSAnswer(recordType, value)
+		wanted[value] = true
+		if !actual[value] {
+			missing = append(missing, value)
+		}
+	}
+
+	var unexpected []string
+	for _, answer := range answers {
+		if !wanted[answer] {
+			unexpected = append(unexpected, answer)
+		}
+	}
+
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return ""
+	}
+
+	var parts []string
+	if len(missing) > 0 {
+		parts = append(parts, "missing "+strings.Join(missing, ", "))
+	}
+	if len(unexpected) > 0 {
+		parts = append(parts, "unexpected "+strings.Join(unexpected, ", "))
+	}
+
+	return "DNS answer mismatch: " + strings.Join(parts, "; ")
+}
+
+// normalizeDNSAnswer puts an answer into a canonical form so that, for
+// example, "Example.com." and "example.com" compare equal
+func normalizeDNSAnswer(recordType, answer string) string {
+	answer = strings.TrimSpace(answer)
+
+	switch recordType {
+	case "A", "AAAA":
+		if ip := net.ParseIP(answer); ip != nil {
+			return ip.String()
+		}
+		return answer
+	case "TXT":
+		// TXT data is case sensitive
+		return answer
+	default:
+		return strings.TrimSuffix(strings.ToLower(answer), ".")
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+
+	"monitoring-tool/models"
+)
+
+// DNS record types and response codes used by the stub server
+const (
+	dnsTypeA    = 1
+	dnsTypeMX   = 15
+	dnsNXDomain = 3
+)
+
+// stubRecords are the answers served by the stub resolver, by name and type
+var stubRecords = map[string]map[uint16][][]byte{
+	"app.example.test.": {
+		dnsTypeA: {{192, 0, 2, 10}, {192, 0, 2, 11}},
+	},
+	"example.test.": {
+		dnsTypeMX: {append([]byte{0, 10}, encodeDNSName("Mail.Example.test.")...)},
+	},
+}
+
+// startStubResolver serves stubRecords over UDP on a loopback port and
+// returns its address
+func startStubResolver(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start stub resolver: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if response := stubResponse(buf[:n]); response != nil {
+				conn.WriteTo(response, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// stubResponse answers a single-question query from stubRecords, or with
+// NXDOMAIN for names it does not know. Additional records such as the EDNS
+// option are ignored.
+func stubResponse(query []byte) []byte {
+	if len(query) < 12 || binary.BigEndian.Uint16(query[4:6]) != 1 {
+		return nil
+	}
+
+	// The question is the name's labels followed by its type and class
+	offset := 12
+	var labels []string
+	for offset < len(query) && query[offset] != 0 {
+		length := int(query[offset])
+		if offset+1+length > len(query) {
+			return nil
+		}
+		labels = append(labels, string(query[offset+1:offset+1+length]))
+		offset += 1 + length
+	}
+	offset++
+	if offset+4 > len(query) {
+		return nil
+	}
+	name := strings.ToLower(strings.Join(labels, ".")) + "."
+	recordType := binary.BigEndian.Uint16(query[offset : offset+2])
+	question := query[12 : offset+4]
+
+	records, known := stubRecords[name]
+	answers := records[recordType]
+
+	response := make([]byte, 12, 512)
+	copy(response[0:2], query[0:2])
+	flags := uint16(0x8180) // response, recursion desired and available
+	if !known {
+		flags |= dnsNXDomain
+	}
+	binary.BigEndian.PutUint16(response[2:4], flags)
+	binary.BigEndian.PutUint16(response[4:6], 1)
+	binary.BigEndian.PutUint16(response[6:8], uint16(len(answers)))
+	response = append(response, question...)
+
+	for _, data := range answers {
+		response = append(response, 0xC0, 12) // pointer to the question name
+		response = binary.BigEndian.AppendUint16(response, recordType)
+		response = binary.BigEndian.AppendUint16(response, 1) // IN
+		response = binary.BigEndian.AppendUint32(response, 60)
+		response = binary.BigEndian.AppendUint16(response, uint16(len(data)))
+		response = append(response, data...)
+	}
+	return response
+}
+
+// encodeDNSName encodes a fully qualified name as DNS labels
+func encodeDNSName(name string) []byte {
+	var encoded []byte
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		encoded = append(encoded, byte(len(label)))
+		encoded = append(encoded, label...)
+	}
+	return append(encoded, 0)
+}
+
+func TestCheckDNS(t *testing.T) {
+	resolver := startStubResolver(t)
+
+	tests := []struct {
+		name       string
+		host       string
+		recordType string
+		expected   []string
+		wantStatus string
+		wantError  string
+		wantAnswer []string
+	}{
+		{
+			name:       "any answer accepted",
+			host:       "app.example.test",
+			recordType: "A",
+			wantStatus: "up",
+			wantAnswer: []string{"192.0.2.10", "192.0.2.11"},
+		},
+		{
+			name:       "expected answers in another order",
+			host:       "app.example.test",
+			recordType: "A",
+			expected:   []string{"192.0.2.11", "192.0.2.10"},
+			wantStatus: "up",
+			wantAnswer: []string{"192.0.2.10", "192.0.2.11"},
+		},
+		{
+			name:       "mismatched answers",
+			host:       "app.example.test",
+			recordType: "A",
+			expected:   []string{"192.0.2.10", "192.0.2.99"},
+			wantStatus: "down",
+			wantError:  "DNS answer mismatch: missing 192.0.2.99; unexpected 192.0.2.11",
+		},
+		{
+			name:       "MX host normalized",
+			host:       "example.test",
+			recordType: "MX",
+			expected:   []string{"mail.example.test."},
+			wantStatus: "up",
+			wantAnswer: []string{"mail.example.test"},
+		},
+		{
+			name:       "unknown name",
+			host:       "missing.example.test",
+			recordType: "A",
+			wantStatus: "down",
+			wantError:  "no such host",
+		},
+	}
+
+	ms := &MonitorService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := ms.checkDNS(models.Monitor{
+				Type:          "dns",
+				URL:           tt.host,
+				Timeout:       5,
+				DNSRecordType: tt.recordType,
+				DNSResolver:   resolver,
+				DNSExpected:   tt.expected,
+			})
+
+			if metric.Status != tt.wantStatus {
+				t.Fatalf("status = %q (error %q), want %q", metric.Status, metric.Error, tt.wantStatus)
+			}
+			if !strings.Contains(metric.Error, tt.wantError) {
+				t.Errorf("error = %q, want it to contain %q", metric.Error, tt.wantError)
+			}
+			if tt.wantAnswer != nil && strings.Join(metric.DNSAnswers, ",") != strings.Join(tt.wantAnswer, ",") {
+				t.Errorf("answers = %v, want %v", metric.DNSAnswers, tt.wantAnswer)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// services/email_notifier.go
+package services
+
+import (
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"monitoring-tool/models"
+)
+
+// sendEmail delivers an event to the channel's recipients through the
+// configured SMTP relay
+func (ns *NotificationService) sendEmail(channel models.NotificationChannel, event models.NotificationEvent) error {
+	cfg := ns.cfg
+	if cfg.SMTPHost == "" {
+		return fmt.Errorf("SMTP relay is not configured")
+	}
+
+	address := net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort))
+	conn, err := net.DialTimeout("tcp", address, deliveryTimeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(deliveryTimeout))
+
+	client, err := smtp.NewClient(conn, cfg.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if cfg.SMTPStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP relay %s does not support STARTTLS", address)
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: cfg.SMTPHost}); err != nil {
+			return err
+		}
+	}
+
+	if cfg.SMTPUsername != "" {
+		auth := smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(cfg.SMTPFrom); err != nil {
+		return err
+	}
+	for _, recipient := range channel.Recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(buildEmail(cfg.SMTPFrom, channel.Recipients, event)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// buildEmail renders a plain text alert email including headers
+func buildEmail(from string, recipients []string, event models.NotificationEvent) []byte {
+	var b strings.Builder
+
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + strings.Join(recipients, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", emailSubject(event)) + "\r\n")
+	b.WriteString("Date: " + event.Timestamp.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+
+	fmt.Fprintf(&b, "Monitor: %s\r\n", event.MonitorName)
+	if event.URL != "" {
+		fmt.Fprintf(&b, "URL: %s\r\n", event.URL)
+	}
+	if event.PreviousStatus != "" {
+		fmt.Fprintf(&b, "Status: %s (was %s)\r\n", strings.ToUpper(event.Status), event.PreviousStatus)
+	} else {
+		fmt.Fprintf(&b, "Status: %s\r\n", strings.ToUpper(event.Status))
+	}
+	if event.StatusCode != 0 {
+		fmt.Fprintf(&b, "HTTP status: %d\r\n", event.StatusCode)
+	}
+	fmt.Fprintf(&b, "Response time: %dms\r\n", event.ResponseTime)
+	if event.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\r\n", event.Error)
+	}
+	if event.IncidentDuration > 0 {
+		fmt.Fprintf(&b, "Incident duration: %s\r\n", time.Duration(event.IncidentDuration)*time.Second)
+	}
+	fmt.Fprintf(&b, "Time: %s\r\n", event.Timestamp.Format(time.RFC1123))
+
+	return []byte(b.String())
+}
+
+// emailSubject summarizes the event for the subject line. Line breaks,
+// which could inject headers, are replaced by spaces.
+func emailSubject(event models.NotificationEvent) string {
+	return strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(eventSummary(event))
+}
+
+// eventSummary describes the event in a single line
+func eventSummary(event models.NotificationEvent) string {
+	switch event.Type {
+	case "monitor_flapping_started":
+		return fmt.Sprintf("[FLAPPING] %s", event.MonitorName)
+	case "monitor_flapping_stopped":
+		return fmt.Sprintf("[STABLE] %s is %s", event.MonitorName, event.Status)
+	}
+
+	switch event.Status {
+	case "down":
+		return fmt.Sprintf("[DOWN] %s", event.MonitorName)
+	case "up":
+		if event.IncidentDuration > 0 {
+			return fmt.Sprintf("[RECOVERED] %s (down for %s)", event.MonitorName, time.Duration(event.IncidentDuration)*time.Second)
+		}
+		return fmt.Sprintf("[RECOVERED] %s", event.MonitorName)
+	default:
+		return fmt.Sprintf("[%s] %s", strings.ToUpper(event.Status), event.MonitorName)
+	}
+}
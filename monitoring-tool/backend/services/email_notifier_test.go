@@ -0,0 +1,192 @@
+package services
+
+import (
+	"bufio"
+	"mime"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-tool/config"
+	"monitoring-tool/models"
+)
+
+// startFakeSMTP accepts a single SMTP session on a loopback port, accepting
+// every command, and sends the message received with DATA on the returned
+// channel
+func startFakeSMTP(t *testing.T) (int, <-chan string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start fake SMTP server: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.Fields(line + " x")[0])
+
+			switch command {
+			case "EHLO", "HELO":
+				reply("250 localhost")
+			case "DATA":
+				reply("354 end data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(strings.TrimPrefix(line, "."))
+				}
+				messages <- data.String()
+				reply("250 ok")
+			case "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, messages
+}
+
+func TestSendEmail(t *testing.T) {
+	tests := []struct {
+		name        string
+		monitorName string
+		wantSubject string
+		wantRaw     string // the Subject header as sent
+	}{
+		{
+			name:        "plain subject",
+			monitorName: "API",
+			wantSubject: "[DOWN] API",
+			wantRaw:     "Subject: [DOWN] API",
+		},
+		{
+			name:        "non-ASCII subject is encoded",
+			monitorName: "Café API",
+			wantSubject: "[DOWN] Café API",
+			wantRaw:     "Subject: =?utf-8?q?",
+		},
+		{
+			name:        "line breaks cannot inject headers",
+			monitorName: "API\r\nBcc: attacker@example.com",
+			wantSubject: "[DOWN] API Bcc: attacker@example.com",
+			wantRaw:     "Subject: [DOWN] API Bcc: attacker@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port, messages := startFakeSMTP(t)
+			ns := &NotificationService{cfg: &config.Config{
+				SMTPHost: "127.0.0.1",
+				SMTPPort: port,
+				SMTPFrom: "monitor@example.com",
+			}}
+
+			channel := models.NotificationChannel{Type: "email", Recipients: []string{"ops@example.com"}}
+			event := models.NotificationEvent{
+				Type:        "monitor_down",
+				MonitorName: tt.monitorName,
+				URL:         "https://example.com",
+				Status:      "down",
+				Error:       "connection refused",
+				Timestamp:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			}
+			if err := ns.sendEmail(channel, event); err != nil {
+				t.Fatalf("sendEmail failed: %v", err)
+			}
+
+			var message string
+			select {
+			case message = <-messages:
+			case <-time.After(5 * time.Second):
+				t.Fatal("fake SMTP server received no message")
+			}
+
+			header, body, found := strings.Cut(message, "\r\n\r\n")
+			if !found {
+				t.Fatalf("message has no header/body separator:\n%s", message)
+			}
+
+			headers := map[string]string{}
+			var subjectLine string
+			for _, line := range strings.Split(header, "\r\n") {
+				key, value, ok := strings.Cut(line, ": ")
+				if !ok {
+					t.Fatalf("malformed header line %q", line)
+				}
+				if _, seen := headers[key]; seen {
+					t.Errorf("duplicate header %s", key)
+				}
+				headers[key] = value
+				if key == "Subject" {
+					subjectLine = line
+				}
+			}
+
+			for _, key := range []string{"From", "To", "Subject", "Date", "MIME-Version", "Content-Type"} {
+				if _, ok := headers[key]; !ok {
+					t.Errorf("missing %s header", key)
+				}
+			}
+			if len(headers) != 6 {
+				t.Errorf("got headers %v, want exactly the six set by buildEmail", headers)
+			}
+			if headers["To"] != "ops@example.com" {
+				t.Errorf("To = %q, want ops@example.com", headers["To"])
+			}
+			if !strings.HasPrefix(subjectLine, tt.wantRaw) {
+				t.Errorf("subject line = %q, want prefix %q", subjectLine, tt.wantRaw)
+			}
+
+			subject, err := new(mime.WordDecoder).DecodeHeader(headers["Subject"])
+			if err != nil {
+				t.Fatalf("failed to decode subject %q: %v", headers["Subject"], err)
+			}
+			if subject != tt.wantSubject {
+				t.Errorf("subject = %q, want %q", subject, tt.wantSubject)
+			}
+
+			for _, want := range []string{"URL: https://example.com\r\n", "Status: DOWN\r\n", "Error: connection refused\r\n"} {
+				if !strings.Contains(body, want) {
+					t.Errorf("body missing %q:\n%s", want, body)
+				}
+			}
+		})
+	}
+}
+
+func TestSendEmailWithoutRelay(t *testing.T) {
+	ns := &NotificationService{cfg: &config.Config{SMTPPort: 587}}
+	err := ns.sendEmail(models.NotificationChannel{Type: "email"}, models.NotificationEvent{})
+	if err == nil || err.Error() != "SMTP relay is not configured" {
+		t.Fatalf("err = %v, want SMTP relay is not configured", err)
+	}
+}
@@ -0,0 +1,297 @@
+// services/escalation_service.go
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/database"
+	"monitoring-tool/models"
+)
+
+// escalationSweepInterval is how often open incidents are checked for due escalation steps
+const escalationSweepInterval = 30 * time.Second
+
+// EscalationService routes incidents of monitors with an escalation policy
+// through the policy's steps until they are acknowledged or resolved
+type EscalationService struct {
+	store         database.Store
+	incidents     *IncidentService
+	notifications *NotificationService
+	maintenance   *MaintenanceService
+	stop          chan struct{}
+	done          chan struct{}
+}
+
+// NewEscalationService creates a new escalation service
+func NewEscalationService(store database.Store, incidents *IncidentService, notifications *NotificationService, maintenance *MaintenanceService) *EscalationService {
+	return &EscalationService{
+		store:         store,
+		incidents:     incidents,
+		notifications: notifications,
+		maintenance:   maintenance,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// HandleTransition alerts the channels of the monitor's escalation policy
+// about a status change. A new incident is sent to the steps that are due
+// immediately, a recovery goes to every step that was alerted during the
+// incident, and other transitions go to the first step.
+func (es *EscalationService) HandleTransition(monitor models.Monitor, previousStatus string, metric models.Metric, incident *models.Incident) {
+	event := TransitionEvent(monitor, previousStatus, metric, incident)
+	if event == nil {
+		return
+	}
+
+	policy, err := es.GetPolicy(*monitor.EscalationPolicyID)
+	if err != nil {
+		log.Printf("Error loading escalation policy for %s, notifying its channels directly: %v", monitor.Name, err)
+		es.notifications.Notify(*event, monitor.NotificationChannelIDs)
+		return
+	}
+
+	switch {
+	case incident != nil && incident.Status == "open":
+		es.escalate(*incident, policy, *event, metric.CheckedAt)
+	case incident != nil && incident.Status == "resolved":
+		if channelIDs := policy.StepChannelIDs(incident.EscalationLevel); len(channelIDs) > 0 {
+			es.notifications.Notify(*event, channelIDs)
+		}
+	default:
+		es.notifications.Notify(*event, policy.Steps[0].ChannelIDs)
+	}
+}
+
+// Route sends an event that is not part of an incident to the monitor's
+// channels: the first step of its escalation policy, if it has one
+func (es *EscalationService) Route(monitor models.Monitor, event models.NotificationEvent) {
+	if monitor.EscalationPolicyID != nil {
+		policy, err := es.GetPolicy(*monitor.EscalationPolicyID)
+		if err == nil {
+			es.notifications.Notify(event, policy.Steps[0].ChannelIDs)
+			return
+		}
+		log.Printf("Error loading escalation policy for %s, notifying its channels directly: %v", monitor.Name, err)
+	}
+
+	es.notifications.Notify(event, monitor.NotificationChannelIDs)
+}
+
+// Run checks open incidents for due escalation steps until Stop is called
+func (es *EscalationService) Run() {
+	defer close(es.done)
+
+	ticker := time.NewTicker(escalationSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			es.sweep()
+		case <-es.stop:
+			return
+		}
+	}
+}
+
+// Stop ends Run, waiting for a sweep in progress to finish. It must only be
+// called once Run has been started.
+func (es *EscalationService) Stop() {
+	close(es.stop)
+	<-es.done
+}
+
+// sweep sends the escalation steps that have become due since the last
+// sweep. Incidents of monitors that are paused, under maintenance or
+// flapping are held until the monitor is checked normally again, as their
+// alerts would be, and escalate from the end of the hold.
+func (es *EscalationService) sweep() {
+	incidents, err := es.incidents.GetEscalatingIncidents()
+	if err != nil {
+		log.Printf("Error loading incidents for escalation: %v", err)
+		return
+	}
+
+	policies := map[primitive.ObjectID]*models.EscalationPolicy{}
+	now := time.Now()
+	for _, incident := range incidents {
+		monitor, err := es.store.Monitors().Get(incident.MonitorID)
+		if err != nil {
+			if !errors.Is(err, database.ErrNotFound) {
+				log.Printf("Error loading monitor for escalation of %s: %v", incident.MonitorName, err)
+			}
+			continue
+		}
+		if !monitor.IsActive || monitor.IsFlapping || es.maintenance.ActiveWindow(*monitor, now) != nil {
+			if incident.EscalationHeldAt == nil {
+				if _, err := es.incidents.HoldEscalation(incident.ID, now); err != nil {
+					log.Printf("Error holding escalation of %s: %v", incident.MonitorName, err)
+				}
+			}
+			continue
+		}
+
+		policyID := *incident.EscalationPolicyID
+		policy, ok := policies[policyID]
+		if !ok {
+			policy, err = es.GetPolicy(policyID)
+			if err != nil {
+				log.Printf("Error loading escalation policy %s: %v", policyID.Hex(), err)
+				continue
+			}
+			policies[policyID] = policy
+		}
+
+		event := models.NotificationEvent{
+			Type:        "monitor_down",
+			MonitorID:   incident.MonitorID.Hex(),
+			MonitorName: incident.MonitorName,
+			URL:         incident.URL,
+			Status:      "down",
+			Error:       incident.LastError,
+			IncidentID:  incident.ID.Hex(),
+			Timestamp:   now,
+		}
+		es.escalate(incident, policy, event, now)
+	}
+}
+
+// escalate sends every step of the policy that is due at now and has not
+// been sent for the incident yet. An incident whose escalation was held
+// resumes at now, as if the last step it sent had just been sent, so the
+// next step waits its full delay after the step before it.
+func (es *EscalationService) escalate(incident models.Incident, policy *models.EscalationPolicy, event models.NotificationEvent, now time.Time) {
+	if incident.EscalationHeldAt != nil {
+		startedAt := now
+		if sent := min(incident.EscalationLevel, len(policy.Steps)); sent > 0 {
+			startedAt = now.Add(-stepDelay(policy.Steps[sent-1]))
+		}
+		resumed, err := es.incidents.ResumeEscalation(incident.ID, startedAt)
+		if err != nil {
+			log.Printf("Error resuming escalation: %v", err)
+			return
+		}
+		if !resumed {
+			return
+		}
+		log.Printf("▶️  Resuming escalation of incident for %s", incident.MonitorName)
+		incident.EscalationStartedAt = &startedAt
+	}
+
+	startedAt := incident.StartedAt
+	if incident.EscalationStartedAt != nil {
+		startedAt = *incident.EscalationStartedAt
+	}
+
+	level := incident.EscalationLevel
+	for level < len(policy.Steps) && now.Sub(startedAt) >= stepDelay(policy.Steps[level]) {
+		level++
+	}
+	if level == incident.EscalationLevel {
+		return
+	}
+
+	advanced, err := es.incidents.AdvanceEscalation(incident.ID, incident.EscalationLevel, level)
+	if err != nil {
+		log.Printf("Error advancing escalation: %v", err)
+		return
+	}
+	if !advanced {
+		return
+	}
+
+	for step := incident.EscalationLevel; step < level; step++ {
+		log.Printf("📣 Escalating incident for %s to step %d of %s", incident.MonitorName, step+1, policy.Name)
+		event.EscalationLevel = step + 1
+		es.notifications.Notify(event, policy.Steps[step].ChannelIDs)
+	}
+}
+
+// stepDelay is how long after the escalation started a step is sent
+func stepDelay(step models.EscalationStep) time.Duration {
+	return time.Duration(step.DelayMinutes) * time.Minute
+}
+
+// CreatePolicy adds a new escalation policy
+func (es *EscalationService) CreatePolicy(policy *models.EscalationPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	found, err := es.notifications.channelsExist(policy.StepChannelIDs(len(policy.Steps)))
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%w: unknown notification channel in steps", models.ErrInvalidEscalationPolicy)
+	}
+
+	if err := es.store.EscalationPolicies().Create(policy); err != nil {
+		return err
+	}
+
+	log.Printf("✅ Created escalation policy: %s (%d steps)", policy.Name, len(policy.Steps))
+
+	return nil
+}
+
+// GetPolicies retrieves all escalation policies
+func (es *EscalationService) GetPolicies() ([]models.EscalationPolicy, error) {
+	return es.store.EscalationPolicies().List()
+}
+
+// GetPolicy retrieves a single escalation policy by ID
+func (es *EscalationService) GetPolicy(id primitive.ObjectID) (*models.EscalationPolicy, error) {
+	policy, err := es.store.EscalationPolicies().Get(id)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("escalation policy not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// CheckPolicyID verifies that a monitor's escalation policy exists
+func (es *EscalationService) CheckPolicyID(id *primitive.ObjectID) error {
+	if id == nil {
+		return nil
+	}
+
+	if _, err := es.GetPolicy(*id); err != nil {
+		if err.Error() == "escalation policy not found" {
+			return fmt.Errorf("%w: unknown escalation_policy_id", models.ErrInvalidMonitor)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DeletePolicy removes an escalation policy. Monitors using it go back to
+// notifying their channels directly.
+func (es *EscalationService) DeletePolicy(id primitive.ObjectID) error {
+	err := es.store.EscalationPolicies().Delete(id)
+	if errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("escalation policy not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := es.store.Monitors().DetachEscalationPolicy(id); err != nil {
+		log.Printf("Error detaching escalation policy from monitors: %v", err)
+	}
+	if err := es.store.Incidents().DetachEscalationPolicy(id); err != nil {
+		log.Printf("Error detaching escalation policy from incidents: %v", err)
+	}
+
+	log.Printf("🗑️  Deleted escalation policy: %s", id.Hex())
+	return nil
+}
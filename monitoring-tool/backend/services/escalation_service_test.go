@@ -0,0 +1,161 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/config"
+	"monitoring-tool/database"
+	"monitoring-tool/models"
+)
+
+// newTestEscalation returns an escalation service on an in-memory store
+// holding a monitor whose incidents follow a three step policy
+func newTestEscalation(t *testing.T) (*EscalationService, *models.Monitor, *models.EscalationPolicy) {
+	t.Helper()
+
+	store := database.NewMemoryStore(24 * time.Hour)
+	incidents := NewIncidentService(store)
+	es := NewEscalationService(store, incidents, NewNotificationService(store, &config.Config{}), NewMaintenanceService(store))
+
+	policy := &models.EscalationPolicy{
+		Name: "On call",
+		Steps: []models.EscalationStep{
+			{ChannelIDs: []primitive.ObjectID{primitive.NewObjectID()}, DelayMinutes: 0},
+			{ChannelIDs: []primitive.ObjectID{primitive.NewObjectID()}, DelayMinutes: 10},
+			{ChannelIDs: []primitive.ObjectID{primitive.NewObjectID()}, DelayMinutes: 30},
+		},
+	}
+	if err := store.EscalationPolicies().Create(policy); err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+
+	monitor := &models.Monitor{Name: "API", URL: "https://api.example.com", Type: "http", IsActive: true, EscalationPolicyID: &policy.ID}
+	if err := store.Monitors().Create(monitor); err != nil {
+		t.Fatalf("failed to create monitor: %v", err)
+	}
+	return es, monitor, policy
+}
+
+// openIncident stores an open incident of the monitor that started at
+// startedAt and has sent level steps
+func openIncident(t *testing.T, es *EscalationService, monitor *models.Monitor, startedAt time.Time, level int) *models.Incident {
+	t.Helper()
+
+	incident := &models.Incident{
+		MonitorID:          monitor.ID,
+		MonitorName:        monitor.Name,
+		Status:             "open",
+		StartedAt:          startedAt,
+		EscalationPolicyID: monitor.EscalationPolicyID,
+		EscalationLevel:    level,
+	}
+	if err := es.store.Incidents().Insert(incident); err != nil {
+		t.Fatalf("failed to insert incident: %v", err)
+	}
+	return incident
+}
+
+// getIncident loads the incident as stored
+func getIncident(t *testing.T, es *EscalationService, id primitive.ObjectID) *models.Incident {
+	t.Helper()
+
+	incident, err := es.incidents.GetIncident(id)
+	if err != nil {
+		t.Fatalf("GetIncident failed: %v", err)
+	}
+	return incident
+}
+
+func TestEscalationRestartsAfterHold(t *testing.T) {
+	es, monitor, policy := newTestEscalation(t)
+
+	// An hour into the incident only the first step was sent before the
+	// monitor started flapping
+	opened := openIncident(t, es, monitor, time.Now().Add(-time.Hour), 1)
+	if err := es.store.Monitors().SetFlapping(monitor.ID, true); err != nil {
+		t.Fatalf("SetFlapping failed: %v", err)
+	}
+	es.sweep()
+	incident := getIncident(t, es, opened.ID)
+	if incident.EscalationHeldAt == nil || incident.EscalationLevel != 1 {
+		t.Fatalf("incident while flapping = %+v, want held at level 1", incident)
+	}
+
+	// When the hold ends the overdue steps are not sent at once
+	if err := es.store.Monitors().SetFlapping(monitor.ID, false); err != nil {
+		t.Fatalf("SetFlapping failed: %v", err)
+	}
+	resumedAt := time.Now()
+	es.sweep()
+	incident = getIncident(t, es, opened.ID)
+	if incident.EscalationHeldAt != nil || incident.EscalationStartedAt == nil || incident.EscalationLevel != 1 {
+		t.Fatalf("incident after the hold = %+v, want resumed at level 1", incident)
+	}
+	if startedAt := *incident.EscalationStartedAt; startedAt.Before(resumedAt.Add(-time.Second)) || startedAt.After(time.Now()) {
+		t.Fatalf("escalation started at %v, want the end of the hold", startedAt)
+	}
+
+	// Each following step waits its full delay after the step before it
+	start := *incident.EscalationStartedAt
+	for _, tt := range []struct {
+		after     time.Duration
+		wantLevel int
+	}{
+		{9 * time.Minute, 1},
+		{10 * time.Minute, 2},
+		{29 * time.Minute, 2},
+		{30 * time.Minute, 3},
+	} {
+		es.escalate(*incident, policy, models.NotificationEvent{}, start.Add(tt.after))
+		incident = getIncident(t, es, opened.ID)
+		if incident.EscalationLevel != tt.wantLevel {
+			t.Fatalf("level %v after the hold = %d, want %d", tt.after, incident.EscalationLevel, tt.wantLevel)
+		}
+	}
+}
+
+func TestEscalationAfterHoldSendsDueFirstStep(t *testing.T) {
+	es, monitor, policy := newTestEscalation(t)
+
+	// A held incident that sent nothing yet gets the immediate step on resume
+	opened := openIncident(t, es, monitor, time.Now().Add(-time.Hour), 0)
+	held := time.Now().Add(-time.Minute)
+	if _, err := es.incidents.HoldEscalation(opened.ID, held); err != nil {
+		t.Fatalf("HoldEscalation failed: %v", err)
+	}
+	if holding, err := es.incidents.HoldEscalation(opened.ID, time.Now()); err != nil || holding {
+		t.Fatalf("second HoldEscalation = %v, %v, want false", holding, err)
+	}
+
+	es.sweep()
+	incident := getIncident(t, es, opened.ID)
+	if incident.EscalationHeldAt != nil || incident.EscalationLevel != 1 {
+		t.Fatalf("incident after the hold = %+v, want resumed at level 1", incident)
+	}
+
+	// The second step is 10 minutes after the end of the hold, not the start
+	// of the incident
+	es.escalate(*incident, policy, models.NotificationEvent{}, incident.EscalationStartedAt.Add(9*time.Minute))
+	if incident = getIncident(t, es, opened.ID); incident.EscalationLevel != 1 {
+		t.Fatalf("level before the second step = %d, want 1", incident.EscalationLevel)
+	}
+}
+
+func TestEscalationServiceStop(t *testing.T) {
+	es, _, _ := newTestEscalation(t)
+	go es.Run()
+
+	stopped := make(chan struct{})
+	go func() {
+		es.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+}
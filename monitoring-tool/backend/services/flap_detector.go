@@ -0,0 +1,88 @@
+// services/flap_detector.go
+package services
+
+import "sync"
+
+// FlapDetector tracks how often each monitor's status changed over its most
+// recent checks. A monitor starts flapping when the share of checks that
+// changed state reaches the start threshold and stops once it falls to the
+// lower stop threshold, so a monitor near the limit does not toggle.
+type FlapDetector struct {
+	window         int
+	startThreshold float64
+	stopThreshold  float64
+
+	mu     sync.Mutex
+	states map[string]*flapState
+}
+
+// flapState is the recent history of one monitor
+type flapState struct {
+	statuses []string
+	flapping bool
+}
+
+// FlapResult describes a monitor's flapping state after a check
+type FlapResult struct {
+	Flapping   bool    // the monitor is flapping after this check
+	Changed    bool    // the check started or stopped flapping
+	ChangeRate float64 // share of recent checks that changed state, 0-1
+}
+
+// NewFlapDetector creates a detector that considers the last window checks.
+// Thresholds are percentages of those checks that changed state.
+func NewFlapDetector(window, startPercent, stopPercent int) *FlapDetector {
+	return &FlapDetector{
+		window:         window,
+		startThreshold: float64(startPercent) / 100,
+		stopThreshold:  float64(stopPercent) / 100,
+		states:         make(map[string]*flapState),
+	}
+}
+
+// Record adds a check result for a monitor and returns its flapping state
+func (fd *FlapDetector) Record(monitorID, status string) FlapResult {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	state, exists := fd.states[monitorID]
+	if !exists {
+		state = &flapState{}
+		fd.states[monitorID] = state
+	}
+
+	state.statuses = append(state.statuses, status)
+	if len(state.statuses) > fd.window {
+		state.statuses = state.statuses[len(state.statuses)-fd.window:]
+	}
+
+	changes := 0
+	for i := 1; i < len(state.statuses); i++ {
+		if state.statuses[i] != state.statuses[i-1] {
+			changes++
+		}
+	}
+	// Divide by the full window so a handful of early checks cannot trip it
+	rate := float64(changes) / float64(fd.window-1)
+
+	result := FlapResult{ChangeRate: rate}
+	switch {
+	case !state.flapping && rate >= fd.startThreshold:
+		state.flapping = true
+		result.Changed = true
+	case state.flapping && rate <= fd.stopThreshold:
+		state.flapping = false
+		result.Changed = true
+	}
+	result.Flapping = state.flapping
+
+	return result
+}
+
+// Forget drops a monitor's history, e.g. when it is paused or deleted
+func (fd *FlapDetector) Forget(monitorID string) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	delete(fd.states, monitorID)
+}
@@ -0,0 +1,99 @@
+package services
+
+import "testing"
+
+func TestFlapDetectorHysteresis(t *testing.T) {
+	// 5 checks have 4 transitions: flapping starts at 2 changes and stops at 1
+	fd := NewFlapDetector(5, 50, 25)
+
+	steps := []struct {
+		status       string
+		wantRate     float64
+		wantFlapping bool
+		wantChanged  bool
+	}{
+		{"up", 0, false, false},
+		{"down", 0.25, false, false},
+		{"up", 0.5, true, true},      // reaches the start threshold
+		{"up", 0.5, true, false},     // stays flapping
+		{"up", 0.5, true, false},     // window is full
+		{"up", 0.25, false, true},    // the first change left the window
+		{"down", 0.25, false, false}, // back above the stop threshold but below the start one
+		{"up", 0.5, true, true},
+		{"down", 0.75, true, false},
+		{"down", 0.75, true, false},
+		{"down", 0.5, true, false},
+		{"down", 0.25, false, true},
+		{"down", 0, false, false},
+	}
+
+	for i, step := range steps {
+		result := fd.Record("api", step.status)
+		if result.ChangeRate != step.wantRate || result.Flapping != step.wantFlapping || result.Changed != step.wantChanged {
+			t.Fatalf("check %d (%s) = %+v, want rate %v, flapping %v, changed %v",
+				i+1, step.status, result, step.wantRate, step.wantFlapping, step.wantChanged)
+		}
+	}
+}
+
+func TestFlapDetectorBetweenThresholds(t *testing.T) {
+	// 9 checks have 8 transitions: 4 changes start flapping and 2 stop it,
+	// and 3 changes leave the state as it is
+	fd := NewFlapDetector(9, 50, 25)
+
+	steps := []struct {
+		status       string
+		wantChanges  int
+		wantFlapping bool
+	}{
+		{"down", 0, false},
+		{"down", 0, false},
+		{"down", 0, false},
+		{"down", 0, false},
+		{"down", 0, false},
+		{"down", 0, false},
+		{"up", 1, false},
+		{"down", 2, false},
+		{"up", 3, false}, // between the thresholds: does not start
+		{"down", 4, true},
+		{"down", 4, true},
+		{"down", 4, true},
+		{"down", 4, true},
+		{"down", 4, true},
+		{"down", 3, true}, // between the thresholds: does not stop
+		{"down", 2, false},
+	}
+
+	for i, step := range steps {
+		result := fd.Record("api", step.status)
+		if result.ChangeRate != float64(step.wantChanges)/8 || result.Flapping != step.wantFlapping {
+			t.Fatalf("check %d (%s) = %+v, want %d of 8 transitions changed and flapping %v",
+				i+1, step.status, result, step.wantChanges, step.wantFlapping)
+		}
+	}
+}
+
+func TestFlapDetectorForget(t *testing.T) {
+	fd := NewFlapDetector(5, 50, 25)
+	for _, status := range []string{"up", "down", "up"} {
+		fd.Record("api", status)
+		fd.Record("web", "up")
+	}
+	if result := fd.Record("api", "down"); !result.Flapping {
+		t.Fatalf("api = %+v, want flapping", result)
+	}
+
+	// Monitors are tracked separately
+	if result := fd.Record("web", "up"); result.Flapping || result.ChangeRate != 0 {
+		t.Fatalf("web = %+v, want a steady monitor", result)
+	}
+
+	// A forgotten monitor starts over with no history
+	fd.Forget("api")
+	if result := fd.Record("api", "up"); result.Flapping || result.Changed || result.ChangeRate != 0 {
+		t.Fatalf("api after Forget = %+v, want no history", result)
+	}
+	if result := fd.Record("api", "down"); result.Flapping || result.ChangeRate != 0.25 {
+		t.Fatalf("api after Forget and a change = %+v, want rate 0.25 and not flapping", result)
+	}
+}
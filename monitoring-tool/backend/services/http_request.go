@@ -0,0 +1,74 @@
+// services/http_request.go
+package services
+
+import (
+	"fmt"
+	"net/http"
+
+	"monitoring-tool/models"
+)
+
+// applyRequestOptions sets the monitor's content type, custom headers and
+// credentials on an outgoing check request
+func applyRequestOptions(req *http.Request, monitor models.Monitor) {
+	if monitor.ContentType != "" {
+		req.Header.Set("Content-Type", monitor.ContentType)
+	}
+
+	for name, value := range monitor.Headers {
+		// The Host header is taken from req.Host, not req.Header
+		if http.CanonicalHeaderKey(name) == "Host" {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+
+	if monitor.Auth != nil {
+		switch monitor.Auth.Type {
+		case "basic":
+			req.SetBasicAuth(monitor.Auth.Username, monitor.Auth.Password)
+		case "bearer":
+			req.Header.Set("Authorization", "Bearer "+monitor.Auth.Token)
+		}
+	}
+}
+
+// defaultMaxRedirects matches the net/http client default
+const defaultMaxRedirects = 10
+
+// redirectRecorder collects the URLs visited while following redirects,
+// starting with the monitor's own URL
+type redirectRecorder struct {
+	chain []string
+}
+
+// clientFor returns a client that shares the service's transport but applies
+// the monitor's redirect policy and records the redirect chain
+func (ms *MonitorService) clientFor(monitor models.Monitor, recorder *redirectRecorder) *http.Client {
+	follow := monitor.FollowRedirects == nil || *monitor.FollowRedirects
+	maxRedirects := monitor.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = defaultMaxRedirects
+	}
+
+	return &http.Client{
+		Transport: ms.httpClient.Transport,
+		Timeout:   ms.httpClient.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !follow {
+				// Return the redirect response itself
+				return http.ErrUseLastResponse
+			}
+			if len(via) > maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+
+			if len(recorder.chain) == 0 {
+				recorder.chain = append(recorder.chain, via[0].URL.String())
+			}
+			recorder.chain = append(recorder.chain, req.URL.String())
+			return nil
+		},
+	}
+}
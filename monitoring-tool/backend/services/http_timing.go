@@ -0,0 +1,98 @@
+// services/http_timing.go
+package services
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"monitoring-tool/models"
+)
+
+// requestTimer records the phases of an HTTP request through httptrace.
+// Callbacks may run on different goroutines, so access is guarded by a mutex.
+type requestTimer struct {
+	mutex sync.Mutex
+
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+
+	dns     time.Duration
+	connect time.Duration
+	tls     time.Duration
+}
+
+// newRequestTimer creates a timer for a request started at start
+func newRequestTimer(start time.Time) *requestTimer {
+	return &requestTimer{start: start}
+}
+
+// trace returns the httptrace hooks that feed the timer
+func (t *requestTimer) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mark(&t.dnsStart)
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.add(&t.dns, &t.dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			t.mark(&t.connectStart)
+		},
+		ConnectDone: func(string, string, error) {
+			t.add(&t.connect, &t.connectStart)
+		},
+		TLSHandshakeStart: func() {
+			t.mark(&t.tlsStart)
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.add(&t.tls, &t.tlsStart)
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.mark(&t.wroteRequest)
+		},
+		GotFirstResponseByte: func() {
+			t.mark(&t.firstByte)
+		},
+	}
+}
+
+// mark stores the current time in field
+func (t *requestTimer) mark(field *time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	*field = time.Now()
+}
+
+// add accumulates the time elapsed since start into total
+func (t *requestTimer) add(total *time.Duration, start *time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if !start.IsZero() {
+		*total += time.Since(*start)
+	}
+}
+
+// finish returns the phase breakdown for a request that completed at end
+func (t *requestTimer) finish(end time.Time) *models.RequestTiming {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	timing := &models.RequestTiming{
+		DNSLookup:    t.dns.Milliseconds(),
+		TCPConnect:   t.connect.Milliseconds(),
+		TLSHandshake: t.tls.Milliseconds(),
+		Total:        end.Sub(t.start).Milliseconds(),
+	}
+	if !t.wroteRequest.IsZero() && !t.firstByte.IsZero() {
+		timing.TimeToFirstByte = t.firstByte.Sub(t.wroteRequest).Milliseconds()
+		timing.ContentTransfer = end.Sub(t.firstByte).Milliseconds()
+	}
+
+	return timing
+}
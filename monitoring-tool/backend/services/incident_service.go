@@ -0,0 +1,198 @@
+// services/incident_service.go
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/database"
+	"monitoring-tool/models"
+)
+
+// IncidentService tracks outages built from monitor status transitions
+type IncidentService struct {
+	store database.Store
+}
+
+// NewIncidentService creates a new incident service
+func NewIncidentService(store database.Store) *IncidentService {
+	return &IncidentService{store: store}
+}
+
+// HandleCheck updates the monitor's incident for a recorded check. A failed
+// check opens an incident (or counts towards the open one) and a successful
+// check after a failure resolves it. The incident is returned when it was
+// opened or resolved by this check.
+func (is *IncidentService) HandleCheck(monitor models.Monitor, previousStatus string, metric models.Metric, wsHub *WebSocketHub) *models.Incident {
+	if metric.Status == "down" {
+		return is.recordFailure(monitor, metric, wsHub)
+	}
+
+	// An outage may also have ended during a maintenance window
+	if previousStatus == "down" || previousStatus == "maintenance" {
+		return is.resolveIncident(monitor.ID, metric.CheckedAt, wsHub)
+	}
+
+	return nil
+}
+
+// recordFailure adds a failed check to the open incident, opening one if needed
+func (is *IncidentService) recordFailure(monitor models.Monitor, metric models.Metric, wsHub *WebSocketHub) *models.Incident {
+	err := is.store.Incidents().RecordFailure(monitor.ID, metric.Error)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, database.ErrNotFound) {
+		log.Printf("Error updating incident: %v", err)
+		return nil
+	}
+
+	incident := models.Incident{
+		MonitorID:          monitor.ID,
+		MonitorName:        monitor.Name,
+		URL:                monitor.URL,
+		Status:             "open",
+		StartedAt:          metric.CheckedAt,
+		FirstError:         metric.Error,
+		LastError:          metric.Error,
+		AffectedChecks:     1,
+		EscalationPolicyID: monitor.EscalationPolicyID,
+	}
+
+	if err := is.store.Incidents().Insert(&incident); err != nil {
+		log.Printf("Error opening incident: %v", err)
+		return nil
+	}
+
+	log.Printf("🚨 Incident opened: %s (%s) - %s", monitor.Name, monitor.URL, metric.Error)
+
+	wsHub.Broadcast <- models.WebSocketMessage{
+		Type:      "incident_opened",
+		Data:      incident,
+		MonitorID: monitor.ID.Hex(),
+	}
+
+	return &incident
+}
+
+// resolveIncident closes the monitor's open incident, if any. wsHub may be
+// nil when there is nobody to notify.
+func (is *IncidentService) resolveIncident(monitorID primitive.ObjectID, resolvedAt time.Time, wsHub *WebSocketHub) *models.Incident {
+	incident, err := is.store.Incidents().FindOpen(monitorID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		log.Printf("Error loading open incident: %v", err)
+		return nil
+	}
+
+	incident.Status = "resolved"
+	incident.ResolvedAt = &resolvedAt
+	incident.Duration = int64(resolvedAt.Sub(incident.StartedAt).Seconds())
+
+	if err := is.store.Incidents().Resolve(incident.ID, resolvedAt, incident.Duration); err != nil {
+		log.Printf("Error resolving incident: %v", err)
+		return nil
+	}
+
+	log.Printf("✅ Incident resolved: %s (%s) after %ds", incident.MonitorName, incident.URL, incident.Duration)
+
+	if wsHub != nil {
+		wsHub.Broadcast <- models.WebSocketMessage{
+			Type:      "incident_resolved",
+			Data:      incident,
+			MonitorID: monitorID.Hex(),
+		}
+	}
+
+	return incident
+}
+
+// ResolveMonitorIncidents closes any open incident for a monitor that is
+// being paused or removed, so it stops escalating. wsHub may be nil.
+func (is *IncidentService) ResolveMonitorIncidents(monitorID primitive.ObjectID, wsHub *WebSocketHub) {
+	is.resolveIncident(monitorID, time.Now(), wsHub)
+}
+
+// AcknowledgeIncident marks an open incident as acknowledged, which stops
+// any further escalation
+func (is *IncidentService) AcknowledgeIncident(id primitive.ObjectID, acknowledgedBy string, wsHub *WebSocketHub) (*models.Incident, error) {
+	incident, err := is.store.Incidents().Acknowledge(id, acknowledgedBy, time.Now())
+	if errors.Is(err, database.ErrNotFound) {
+		existing, err := is.GetIncident(id)
+		if err != nil {
+			return nil, err
+		}
+		if existing.Status != "open" {
+			return nil, fmt.Errorf("incident is already resolved")
+		}
+		return nil, fmt.Errorf("incident is already acknowledged")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🔕 Incident acknowledged: %s (%s)", incident.MonitorName, incident.URL)
+
+	wsHub.Broadcast <- models.WebSocketMessage{
+		Type:      "incident_acknowledged",
+		Data:      incident,
+		MonitorID: incident.MonitorID.Hex(),
+	}
+
+	return incident, nil
+}
+
+// GetIncident retrieves a single incident by ID
+func (is *IncidentService) GetIncident(id primitive.ObjectID) (*models.Incident, error) {
+	incident, err := is.store.Incidents().Get(id)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("incident not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// GetEscalatingIncidents retrieves open, unacknowledged incidents that
+// follow an escalation policy
+func (is *IncidentService) GetEscalatingIncidents() ([]models.Incident, error) {
+	return is.store.Incidents().List(database.IncidentFilter{Escalating: true}, 0)
+}
+
+// AdvanceEscalation moves an incident from one escalation level to the next.
+// It reports false when the incident was acknowledged, resolved or already
+// advanced in the meantime, so each step is only sent once.
+func (is *IncidentService) AdvanceEscalation(id primitive.ObjectID, from, to int) (bool, error) {
+	return is.store.Incidents().AdvanceEscalation(id, from, to)
+}
+
+// HoldEscalation holds an incident's escalation from at. It reports false
+// when the escalation was already held or the incident no longer escalates.
+func (is *IncidentService) HoldEscalation(id primitive.ObjectID, at time.Time) (bool, error) {
+	return is.store.Incidents().HoldEscalation(id, at)
+}
+
+// ResumeEscalation ends the hold of an incident's escalation, counting step
+// delays from startedAt. It reports false when the escalation was not held,
+// so a hold only ends once.
+func (is *IncidentService) ResumeEscalation(id primitive.ObjectID, startedAt time.Time) (bool, error) {
+	return is.store.Incidents().ResumeEscalation(id, startedAt)
+}
+
+// GetIncidents retrieves the most recent incidents, optionally filtered by status
+func (is *IncidentService) GetIncidents(status string, limit int) ([]models.Incident, error) {
+	return is.store.Incidents().List(database.IncidentFilter{Status: status}, limit)
+}
+
+// GetMonitorIncidents retrieves the most recent incidents for a monitor
+func (is *IncidentService) GetMonitorIncidents(monitorID primitive.ObjectID, limit int) ([]models.Incident, error) {
+	return is.store.Incidents().List(database.IncidentFilter{MonitorID: &monitorID}, limit)
+}
@@ -0,0 +1,151 @@
+// services/json_assertions.go
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"monitoring-tool/jsonpath"
+	"monitoring-tool/models"
+)
+
+// checkJSONAssertions evaluates every JSON assertion against the body and
+// returns the per-assertion results. The error describes the first failure.
+func checkJSONAssertions(assertions []models.JSONAssertion, body []byte) ([]models.AssertionResult, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("JSON assertion failed: response is not valid JSON: %v", err)
+	}
+
+	results := make([]models.AssertionResult, 0, len(assertions))
+	var firstFailure error
+
+	for _, assertion := range assertions {
+		result := evaluateJSONAssertion(assertion, document)
+		results = append(results, result)
+
+		if !result.Passed && firstFailure == nil {
+			firstFailure = fmt.Errorf("JSON assertion failed: %s", describeAssertionResult(result))
+		}
+	}
+
+	return results, firstFailure
+}
+
+// evaluateJSONAssertion checks a single assertion against a decoded document
+func evaluateJSONAssertion(assertion models.JSONAssertion, document interface{}) models.AssertionResult {
+	result := models.AssertionResult{
+		Path:     assertion.Path,
+		Operator: assertion.Operator,
+	}
+	if assertion.Operator != "exists" {
+		result.Expected = assertion.Value
+	}
+
+	path, err := jsonpath.Compile(assertion.Path)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	value, found := path.Lookup(document)
+	if found {
+		result.Actual = formatJSONValue(value)
+	}
+
+	switch assertion.Operator {
+	case "exists":
+		result.Passed = found
+	case "equals", "not_equals":
+		if !found {
+			result.Error = "path not found"
+			return result
+		}
+		equal := jsonValueEquals(value, assertion.Value)
+		result.Passed = equal == (assertion.Operator == "equals")
+	case "less_than":
+		if !found {
+			result.Error = "path not found"
+			return result
+		}
+		actual, ok := value.(json.Number)
+		if !ok {
+			result.Error = "value is not a number"
+			return result
+		}
+		actualFloat, err := actual.Float64()
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		expected, err := strconv.ParseFloat(assertion.Value, 64)
+		if err != nil {
+			result.Error = "expected value is not a number"
+			return result
+		}
+		result.Passed = actualFloat < expected
+	default:
+		result.Error = "unsupported operator " + assertion.Operator
+	}
+
+	return result
+}
+
+// jsonValueEquals compares a decoded JSON value with the expected value
+// given as a string. Numbers compare numerically, so "1" equals 1.0.
+func jsonValueEquals(value interface{}, expected string) bool {
+	if number, ok := value.(json.Number); ok {
+		actual, err1 := number.Float64()
+		want, err2 := strconv.ParseFloat(expected, 64)
+		if err1 == nil && err2 == nil {
+			return actual == want
+		}
+	}
+
+	return formatJSONValue(value) == expected
+}
+
+// formatJSONValue renders a decoded JSON value for comparison and display.
+// Strings are returned without quotes; other values as compact JSON.
+func formatJSONValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return "null"
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
+
+// describeAssertionResult formats a failed assertion for Metric.Error
+func describeAssertionResult(result models.AssertionResult) string {
+	var b strings.Builder
+	b.WriteString(result.Path)
+	b.WriteString(" ")
+	b.WriteString(result.Operator)
+	if result.Operator != "exists" {
+		fmt.Fprintf(&b, " %q", result.Expected)
+	}
+
+	switch {
+	case result.Error != "":
+		fmt.Fprintf(&b, " (%s)", result.Error)
+	case result.Operator != "exists":
+		fmt.Fprintf(&b, " (got %q)", result.Actual)
+	}
+
+	return b.String()
+}
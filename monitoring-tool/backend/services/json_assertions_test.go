@@ -0,0 +1,106 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"monitoring-tool/models"
+)
+
+// paddedJSON returns a document of exactly size bytes whose status is ok
+func paddedJSON(size int) string {
+	prefix, suffix := `{"status":"ok","padding":"`, `"}`
+	return prefix + strings.Repeat("x", size-len(prefix)-len(suffix)) + suffix
+}
+
+func TestCheckHTTPJSONAssertions(t *testing.T) {
+	statusOK := []models.JSONAssertion{{Path: "$.status", Operator: "equals", Value: "ok"}}
+
+	tests := []struct {
+		name       string
+		body       string
+		assertions []models.JSONAssertion
+		bodyChecks []models.BodyAssertion
+		wantStatus string
+		wantError  string
+	}{
+		{
+			name:       "passing",
+			body:       `{"status":"ok","db":{"latency":12}}`,
+			assertions: []models.JSONAssertion{{Path: "$.status", Operator: "equals", Value: "ok"}, {Path: "$.db.latency", Operator: "less_than", Value: "50"}},
+			wantStatus: "up",
+		},
+		{
+			name:       "failing",
+			body:       `{"status":"degraded"}`,
+			assertions: statusOK,
+			wantStatus: "down",
+			wantError:  `JSON assertion failed: $.status equals "ok" (got "degraded")`,
+		},
+		{
+			name:       "missing path",
+			body:       `{"state":"ok"}`,
+			assertions: statusOK,
+			wantStatus: "down",
+			wantError:  `JSON assertion failed: $.status equals "ok" (path not found)`,
+		},
+		{
+			name:       "not JSON",
+			body:       `<html>ok</html>`,
+			assertions: statusOK,
+			wantStatus: "down",
+			wantError:  "JSON assertion failed: response is not valid JSON",
+		},
+		{
+			name:       "body at the limit",
+			body:       paddedJSON(maxJSONBodyBytes),
+			assertions: statusOK,
+			wantStatus: "up",
+		},
+		{
+			// Cut at the limit, the document would be reported as invalid JSON
+			name:       "body past the limit",
+			body:       paddedJSON(maxJSONBodyBytes + 1),
+			assertions: statusOK,
+			wantStatus: "down",
+			wantError:  "JSON assertion failed: response is larger than 1024 KB",
+		},
+		{
+			name:       "body assertions see the first 64 KB of a large body",
+			body:       paddedJSON(maxJSONBodyBytes + 1),
+			assertions: statusOK,
+			bodyChecks: []models.BodyAssertion{{Type: "contains", Value: `"status":"ok"`}},
+			wantStatus: "down",
+			wantError:  "JSON assertion failed: response is larger than 1024 KB",
+		},
+	}
+
+	ms := &MonitorService{httpClient: &http.Client{}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			metric := ms.checkHTTP(models.Monitor{
+				Type:           "http",
+				URL:            server.URL,
+				Method:         "GET",
+				Timeout:        5,
+				JSONAssertions: tt.assertions,
+				BodyAssertions: tt.bodyChecks,
+			})
+
+			if metric.Status != tt.wantStatus {
+				t.Fatalf("status = %q (error %q), want %q", metric.Status, metric.Error, tt.wantStatus)
+			}
+			if !strings.HasPrefix(metric.Error, tt.wantError) {
+				t.Errorf("error = %q, want it to start with %q", metric.Error, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// services/maintenance_service.go
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/database"
+	"monitoring-tool/models"
+)
+
+// MaintenanceService manages planned maintenance windows
+type MaintenanceService struct {
+	store database.Store
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(store database.Store) *MaintenanceService {
+	return &MaintenanceService{store: store}
+}
+
+// ActiveWindow returns the maintenance window covering the monitor at the
+// given time, or nil when the monitor is not under maintenance
+func (ms *MaintenanceService) ActiveWindow(monitor models.Monitor, at time.Time) *models.MaintenanceWindow {
+	windows, err := ms.store.MaintenanceWindows().ListForMonitor(monitor, at)
+	if err != nil {
+		log.Printf("Error loading maintenance windows: %v", err)
+		return nil
+	}
+
+	for _, window := range windows {
+		if window.AppliesTo(monitor) && window.ActiveAt(at) {
+			return &window
+		}
+	}
+
+	return nil
+}
+
+// CreateWindow adds a new maintenance window
+func (ms *MaintenanceService) CreateWindow(window *models.MaintenanceWindow) error {
+	if err := window.Validate(); err != nil {
+		return err
+	}
+
+	if err := ms.store.MaintenanceWindows().Create(window); err != nil {
+		return err
+	}
+
+	log.Printf("✅ Created maintenance window: %s", window.Name)
+
+	return nil
+}
+
+// GetWindows retrieves all maintenance windows
+func (ms *MaintenanceService) GetWindows() ([]models.MaintenanceWindow, error) {
+	return ms.store.MaintenanceWindows().List()
+}
+
+// DeleteWindow removes a maintenance window
+func (ms *MaintenanceService) DeleteWindow(id primitive.ObjectID) error {
+	err := ms.store.MaintenanceWindows().Delete(id)
+	if errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("maintenance window not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("🗑️  Deleted maintenance window: %s", id.Hex())
+	return nil
+}
@@ -3,90 +3,295 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"net/http/httptrace"
+	"strings"
 	"sync"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	//"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"monitoring-tool/database"
 	"monitoring-tool/models"
 )
 
 type MonitorService struct {
-	db          *database.MongoDB
-	httpClient  *http.Client
-	activeJobs  map[string]chan bool // for stopping individual monitor jobs
-	jobsMutex   sync.RWMutex
-	rateLimiter *time.Ticker
-    semaphore   chan struct{}
+	store         database.Store
+	incidents     *IncidentService
+	notifications *NotificationService
+	escalations   *EscalationService
+	flaps         *FlapDetector
+	maintenance   *MaintenanceService
+	httpClient    *http.Client
+	activeJobs    map[string]chan bool // for stopping individual monitor jobs
+	jobsMutex     sync.RWMutex
+	rateLimiter   *time.Ticker
+	semaphore     chan struct{}
+	retention     time.Duration // metrics retention for monitors without their own
 }
 
 // NewMonitorService creates a new monitor service
-func NewMonitorService(db *database.MongoDB, maxConcurrent int) *MonitorService {
-    return &MonitorService{
-        db: db,
-        httpClient: &http.Client{
-            Timeout: 30 * time.Second,
-            Transport: &http.Transport{
-                MaxIdleConns:        100,
-                MaxIdleConnsPerHost: 10,
-                IdleConnTimeout:     90 * time.Second,
-                DisableKeepAlives:   false,
-            },
-        },
-        activeJobs:  make(map[string]chan bool),
-        semaphore:   make(chan struct{}, maxConcurrent),
-    }
+func NewMonitorService(store database.Store, incidents *IncidentService, notifications *NotificationService, escalations *EscalationService, flaps *FlapDetector, maintenance *MaintenanceService, maxConcurrent int, retention time.Duration) *MonitorService {
+	return &MonitorService{
+		store:         store,
+		incidents:     incidents,
+		notifications: notifications,
+		escalations:   escalations,
+		flaps:         flaps,
+		maintenance:   maintenance,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 10,
+				IdleConnTimeout:     90 * time.Second,
+				DisableKeepAlives:   false,
+			},
+		},
+		activeJobs: make(map[string]chan bool),
+		semaphore:  make(chan struct{}, maxConcurrent),
+		retention:  retention,
+	}
 }
 
 // CreateMonitor adds a new monitor to the database
 func (ms *MonitorService) CreateMonitor(monitor *models.Monitor) error {
-	collection := ms.db.GetCollection(database.MonitorsCollection)
-	
-	// Check if URL already exists
-	filter := bson.M{"url": monitor.URL}
-	count, err := collection.CountDocuments(context.Background(), filter)
+	if err := monitor.Validate(); err != nil {
+		return err
+	}
+	if monitor.RetentionDays != 0 {
+		if err := ms.checkRetention(); err != nil {
+			return err
+		}
+	}
+	if err := ms.notifications.CheckChannelIDs(monitor.NotificationChannelIDs); err != nil {
+		return err
+	}
+	if err := ms.escalations.CheckPolicyID(monitor.EscalationPolicyID); err != nil {
+		return err
+	}
+
+	// Check if another monitor already checks the same target
+	exists, err := ms.store.Monitors().TargetExists(monitor, nil)
 	if err != nil {
 		return err
 	}
-	if count > 0 {
-		return fmt.Errorf("monitor with URL %s already exists", monitor.URL)
+	if exists {
+		return targetExists(monitor)
 	}
 
 	// Insert the monitor
-	result, err := collection.InsertOne(context.Background(), monitor)
-	if err != nil {
+	if err := ms.store.Monitors().Create(monitor); err != nil {
 		return err
 	}
 
-	monitor.ID = result.InsertedID.(primitive.ObjectID)
 	log.Printf("✅ Created monitor: %s (%s)", monitor.Name, monitor.URL)
-	
+
 	return nil
 }
 
 // GetMonitors retrieves all monitors
 func (ms *MonitorService) GetMonitors() ([]models.Monitor, error) {
-	collection := ms.db.GetCollection(database.MonitorsCollection)
-	
-	cursor, err := collection.Find(context.Background(), bson.M{})
+	return ms.store.Monitors().List()
+}
+
+// GetMonitor retrieves a single monitor by ID
+func (ms *MonitorService) GetMonitor(id primitive.ObjectID) (*models.Monitor, error) {
+	monitor, err := ms.store.Monitors().Get(id)
+	return monitor, monitorNotFound(err)
+}
+
+// targetExists reports that another monitor already checks the monitor's target
+func targetExists(monitor *models.Monitor) error {
+	if monitor.Type == "dns" {
+		return fmt.Errorf("dns %s monitor with URL %s already exists", monitor.DNSRecordType, monitor.URL)
+	}
+	return fmt.Errorf("%s monitor with URL %s already exists", monitor.Type, monitor.URL)
+}
+
+// checkRetention rejects a per-monitor retention when the store expires
+// every metric after the same time
+func (ms *MonitorService) checkRetention() error {
+	if !ms.store.Metrics().PerMonitorRetention() {
+		return fmt.Errorf("%w: retention_days is not supported when metrics are stored in a time-series collection", models.ErrInvalidMonitor)
+	}
+	return nil
+}
+
+// monitorNotFound reports a missing monitor with the message handlers expect
+func monitorNotFound(err error) error {
+	if errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("monitor not found")
+	}
+	return err
+}
+
+// UpdateMonitor applies a partial update to a monitor and restarts its
+// monitoring job so that the new settings take effect immediately
+func (ms *MonitorService) UpdateMonitor(id primitive.ObjectID, req *models.UpdateMonitorRequest, wsHub *WebSocketHub) (*models.Monitor, error) {
+	monitor, err := ms.GetMonitor(id)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(context.Background())
 
-	var monitors []models.Monitor
-	if err = cursor.All(context.Background(), &monitors); err != nil {
+	previous := *monitor
+	previousRetention := monitor.RetentionDays
+	req.ApplyTo(monitor)
+	if err := monitor.Validate(); err != nil {
+		return nil, err
+	}
+	// Clearing a retention set before metrics became a time-series collection is allowed
+	if monitor.RetentionDays != previousRetention && monitor.RetentionDays != 0 {
+		if err := ms.checkRetention(); err != nil {
+			return nil, err
+		}
+	}
+	if err := ms.notifications.CheckChannelIDs(monitor.NotificationChannelIDs); err != nil {
+		return nil, err
+	}
+	if err := ms.escalations.CheckPolicyID(monitor.EscalationPolicyID); err != nil {
 		return nil, err
 	}
 
-	return monitors, nil
+	// Check if the new target is already checked by another monitor
+	if monitor.URL != previous.URL || monitor.Type != previous.Type || monitor.DNSRecordType != previous.DNSRecordType {
+		exists, err := ms.store.Monitors().TargetExists(monitor, &id)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, targetExists(monitor)
+		}
+	}
+
+	monitor.UpdatedAt = time.Now()
+	if err := ms.store.Monitors().UpdateSettings(monitor); err != nil {
+		return nil, monitorNotFound(err)
+	}
+
+	// Re-date the expiry of stored metrics for the new retention
+	if monitor.RetentionDays != previousRetention && ms.store.Metrics().PerMonitorRetention() {
+		if err := ms.store.Metrics().SetRetention(id, monitor.MetricsRetention(ms.retention)); err != nil {
+			log.Printf("Error applying retention to metrics of %s: %v", monitor.Name, err)
+		}
+	}
+
+	// Replace the running job so the new interval, timeout and URL are used
+	if monitor.IsActive {
+		ms.startMonitorJob(*monitor, wsHub)
+	}
+
+	log.Printf("✏️  Updated monitor: %s (%s)", monitor.Name, monitor.URL)
+	return monitor, nil
+}
+
+// PauseMonitor stops monitoring a monitor without deleting it or its metrics
+func (ms *MonitorService) PauseMonitor(id primitive.ObjectID, req *models.PauseMonitorRequest, wsHub *WebSocketHub) (*models.Monitor, error) {
+	monitor, err := ms.store.Monitors().Pause(id, req.PausedBy, req.Reason, time.Now())
+	if err != nil {
+		return nil, monitorNotFound(err)
+	}
+
+	ms.stopMonitorJob(id.Hex())
+	ms.flaps.Forget(id.Hex())
+
+	// A paused monitor is not checked, so its outage can no longer be tracked
+	ms.incidents.ResolveMonitorIncidents(id, wsHub)
+	ms.broadcastMonitorStatus(monitor, wsHub)
+
+	log.Printf("⏸️  Paused monitor: %s (%s)", monitor.Name, monitor.URL)
+	return monitor, nil
+}
+
+// ResumeMonitor restarts monitoring for a paused monitor
+func (ms *MonitorService) ResumeMonitor(id primitive.ObjectID, wsHub *WebSocketHub) (*models.Monitor, error) {
+	monitor, err := ms.store.Monitors().Resume(id, time.Now())
+	if err != nil {
+		return nil, monitorNotFound(err)
+	}
+
+	ms.startMonitorJob(*monitor, wsHub)
+	ms.broadcastMonitorStatus(monitor, wsHub)
+
+	log.Printf("▶️  Resumed monitor: %s (%s)", monitor.Name, monitor.URL)
+	return monitor, nil
+}
+
+// broadcastMonitorStatus notifies dashboards that a monitor was paused or resumed
+func (ms *MonitorService) broadcastMonitorStatus(monitor *models.Monitor, wsHub *WebSocketHub) {
+	wsHub.Broadcast <- models.WebSocketMessage{
+		Type: "monitor_status",
+		Data: models.MonitorStatusUpdate{
+			MonitorID:   monitor.ID.Hex(),
+			Status:      monitor.Status,
+			IsActive:    monitor.IsActive,
+			PausedAt:    monitor.PausedAt,
+			PausedBy:    monitor.PausedBy,
+			PauseReason: monitor.PauseReason,
+			Timestamp:   time.Now(),
+		},
+		MonitorID: monitor.ID.Hex(),
+	}
+	ms.broadcastDashboardStats(wsHub)
+}
+
+// GetDashboardStats summarizes the current status of all active monitors
+func (ms *MonitorService) GetDashboardStats() (models.DashboardStats, error) {
+	monitors, err := ms.GetMonitors()
+	if err != nil {
+		return models.DashboardStats{}, err
+	}
+
+	stats := models.DashboardStats{TotalMonitors: len(monitors)}
+
+	var totalUptime float64
+	var totalResponseTime int64
+	for _, monitor := range monitors {
+		if !monitor.IsActive {
+			continue
+		}
+		stats.ActiveMonitors++
+
+		switch monitor.CurrentStatus {
+		case "up":
+			stats.UpMonitors++
+		case "degraded":
+			stats.DegradedMonitors++
+		case "down":
+			stats.DownMonitors++
+		case "maintenance":
+			stats.MaintenanceMonitors++
+		}
+
+		totalUptime += monitor.UptimePercentage
+		totalResponseTime += int64(monitor.CurrentResponse)
+	}
+
+	// Calculate averages
+	if stats.ActiveMonitors > 0 {
+		stats.OverallUptime = totalUptime / float64(stats.ActiveMonitors)
+		stats.AverageResponse = float64(totalResponseTime) / float64(stats.ActiveMonitors)
+	}
+
+	return stats, nil
+}
+
+// broadcastDashboardStats sends the current dashboard counters to all clients
+func (ms *MonitorService) broadcastDashboardStats(wsHub *WebSocketHub) {
+	stats, err := ms.GetDashboardStats()
+	if err != nil {
+		log.Printf("Error calculating dashboard stats: %v", err)
+		return
+	}
+
+	wsHub.Broadcast <- models.WebSocketMessage{
+		Type: "dashboard_stats",
+		Data: stats,
+	}
 }
 
 // DeleteMonitor removes a monitor and stops its monitoring job
@@ -95,52 +300,45 @@ func (ms *MonitorService) DeleteMonitor(id primitive.ObjectID) error {
 	ms.stopMonitorJob(id.Hex())
 
 	// Delete from database
-	collection := ms.db.GetCollection(database.MonitorsCollection)
-	result, err := collection.DeleteOne(context.Background(), bson.M{"_id": id})
-	if err != nil {
-		return err
+	if err := ms.store.Monitors().Delete(id); err != nil {
+		return monitorNotFound(err)
 	}
 
-	if result.DeletedCount == 0 {
-		return fmt.Errorf("monitor not found")
-	}
+	// Close any outage left open by the deleted monitor
+	ms.incidents.ResolveMonitorIncidents(id, nil)
+	ms.flaps.Forget(id.Hex())
 
 	log.Printf("🗑️  Deleted monitor: %s", id.Hex())
 	return nil
 }
 
+// maxRawMetrics caps the raw checks returned for one range. Ranges holding
+// more checks are shown from rollups unless raw checks are asked for.
+const maxRawMetrics = 1000
+
 // GetMetrics retrieves metrics for a specific monitor
 func (ms *MonitorService) GetMetrics(monitorID primitive.ObjectID, hours int) ([]models.Metric, error) {
-	collection := ms.db.GetCollection(database.MetricsCollection)
-	
-	// Query last N hours of data
+	// Query last N hours of data, newest first, limited to maxRawMetrics records
 	startTime := time.Now().Add(-time.Duration(hours) * time.Hour)
-	filter := bson.M{
-		"monitor_id": monitorID,
-		"checked_at": bson.M{"$gte": startTime},
-	}
-
-	// Sort by checked_at descending, limit to 1000 records
-	opts := options.Find().SetSort(bson.M{"checked_at": -1}).SetLimit(1000)
-	
-	cursor, err := collection.Find(context.Background(), filter, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(context.Background())
-
-	var metrics []models.Metric
-	if err = cursor.All(context.Background(), &metrics); err != nil {
-		return nil, err
-	}
+	return ms.store.Metrics().Since(monitorID, startTime, maxRawMetrics)
+}
 
-	return metrics, nil
+// GetRollups returns a monitor's rollups of a resolution covering the last
+// N hours, newest first
+func (ms *MonitorService) GetRollups(monitorID primitive.ObjectID, resolution string, hours int) ([]models.MetricRollup, error) {
+	startTime := time.Now().Add(-time.Duration(hours) * time.Hour).Truncate(models.ResolutionDuration(resolution))
+	return ms.store.Rollups().Since(monitorID, resolution, startTime)
 }
 
 // StartMonitoring starts monitoring all active monitors
 func (ms *MonitorService) StartMonitoring(wsHub *WebSocketHub) {
 	log.Println("🔄 Starting monitoring service...")
 
+	// Flap history is kept in memory, so no monitor is flapping after a restart
+	if err := ms.store.Monitors().ClearFlapping(); err != nil {
+		log.Printf("Error resetting flapping monitors: %v", err)
+	}
+
 	// Start monitoring existing monitors
 	monitors, err := ms.GetMonitors()
 	if err != nil {
@@ -160,13 +358,13 @@ func (ms *MonitorService) StartMonitoring(wsHub *WebSocketHub) {
 // startMonitorJob starts a monitoring job for a specific monitor
 func (ms *MonitorService) startMonitorJob(monitor models.Monitor, wsHub *WebSocketHub) {
 	monitorID := monitor.ID.Hex()
-	
+
 	ms.jobsMutex.Lock()
 	// Stop existing job if running
 	if stopChan, exists := ms.activeJobs[monitorID]; exists {
 		close(stopChan)
 	}
-	
+
 	// Create new stop channel
 	stopChan := make(chan bool)
 	ms.activeJobs[monitorID] = stopChan
@@ -180,12 +378,12 @@ func (ms *MonitorService) startMonitorJob(monitor models.Monitor, wsHub *WebSock
 		defer ticker.Stop()
 
 		// Run initial check immediately
-		ms.checkEndpoint(monitor, wsHub)
+		ms.checkEndpoint(monitor, stopChan, wsHub)
 
 		for {
 			select {
 			case <-ticker.C:
-				ms.checkEndpoint(monitor, wsHub)
+				ms.checkEndpoint(monitor, stopChan, wsHub)
 			case <-stopChan:
 				log.Printf("🛑 Stopped monitoring job: %s", monitor.Name)
 				return
@@ -205,71 +403,222 @@ func (ms *MonitorService) stopMonitorJob(monitorID string) {
 	}
 }
 
-// checkEndpoint performs a health check on an endpoint
-func (ms *MonitorService) checkEndpoint(monitor models.Monitor, wsHub *WebSocketHub) {
+// checkEndpoint performs a health check on an endpoint. A failed check is
+// confirmed with up to monitor.Retries further attempts before it is recorded.
+func (ms *MonitorService) checkEndpoint(monitor models.Monitor, stopChan <-chan bool, wsHub *WebSocketHub) {
+	metric := ms.runCheck(monitor)
+
+	var attempts []models.CheckAttempt
+	for retry := 0; metric.Status == "down" && retry < monitor.Retries; retry++ {
+		attempts = append(attempts, checkAttemptFrom(metric))
+
+		// Wait before confirming, giving up if the job is stopped meanwhile
+		select {
+		case <-time.After(time.Duration(monitor.RetryDelay) * time.Second):
+		case <-stopChan:
+			return
+		}
+
+		metric = ms.runCheck(monitor)
+	}
+
+	if len(attempts) > 0 {
+		metric.Attempts = append(attempts, checkAttemptFrom(metric))
+	}
+
+	// Drop the result if the job was stopped while the check was running, so
+	// a paused or deleted monitor does not change status or alert afterwards
+	select {
+	case <-stopChan:
+		return
+	default:
+	}
+
+	ms.recordMetric(monitor, metric, wsHub)
+}
+
+// runCheck performs a single check attempt according to the monitor type
+func (ms *MonitorService) runCheck(monitor models.Monitor) models.Metric {
+	ms.semaphore <- struct{}{}
+	defer func() { <-ms.semaphore }()
+
+	switch monitor.Type {
+	case "tcp":
+		return ms.checkTCP(monitor)
+	case "dns":
+		return ms.checkDNS(monitor)
+	default:
+		return ms.checkHTTP(monitor)
+	}
+}
+
+// checkAttemptFrom summarizes a single attempt for the metric's attempt list
+func checkAttemptFrom(metric models.Metric) models.CheckAttempt {
+	return models.CheckAttempt{
+		Status:       metric.Status,
+		StatusCode:   metric.StatusCode,
+		ResponseTime: metric.ResponseTime,
+		Error:        metric.Error,
+		CheckedAt:    time.Now(),
+	}
+}
 
-	 ms.semaphore <- struct{}{}
-    defer func() { <-ms.semaphore }()
+// checkHTTP performs an HTTP request against the monitor's URL
+func (ms *MonitorService) checkHTTP(monitor models.Monitor) models.Metric {
 	startTime := time.Now()
 
 	// Create HTTP request
-	req, err := http.NewRequest(monitor.Method, monitor.URL, nil)
+	var reqBody io.Reader
+	if monitor.Body != "" {
+		reqBody = strings.NewReader(monitor.Body)
+	}
+	req, err := http.NewRequest(monitor.Method, monitor.URL, reqBody)
 	if err != nil {
-		ms.recordMetric(monitor, "down", 0, 0, err.Error(), wsHub)
-		return
+		return models.Metric{Status: "down", Error: err.Error()}
 	}
 
 	// Set timeout for this specific request
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(monitor.Timeout)*time.Second)
 	defer cancel()
-	req = req.WithContext(ctx)
 
-	// Set user agent
+	// Trace each phase of the request
+	timer := newRequestTimer(startTime)
+	req = req.WithContext(httptrace.WithClientTrace(ctx, timer.trace()))
+
+	// Set user agent, then any custom headers and credentials
 	req.Header.Set("User-Agent", "RealtimeMonitor/1.0")
+	applyRequestOptions(req, monitor)
 
-	// Perform the request
-	resp, err := ms.httpClient.Do(req)
+	// Perform the request, following redirects according to the monitor's policy
+	redirects := &redirectRecorder{}
+	resp, err := ms.clientFor(monitor, redirects).Do(req)
 	responseTime := time.Since(startTime).Milliseconds()
 
 	if err != nil {
-		ms.recordMetric(monitor, "down", 0, responseTime, err.Error(), wsHub)
-		return
+		return models.Metric{
+			Status:        "down",
+			ResponseTime:  responseTime,
+			Error:         err.Error(),
+			TLS:           tlsInfoFromError(err, req.URL.Hostname()),
+			RedirectChain: redirects.chain,
+			Timing:        timer.finish(time.Now()),
+		}
 	}
 	defer resp.Body.Close()
 
+	// Read (a bounded prefix of) the body so the transfer phase is measured
+	bodyLimit := int64(maxBodyBytes)
+	if len(monitor.JSONAssertions) > 0 {
+		bodyLimit = maxJSONBodyBytes
+	}
+	body, truncated, readErr := readBody(resp, bodyLimit)
+
+	metric := models.Metric{
+		Status:        "up",
+		StatusCode:    resp.StatusCode,
+		ResponseTime:  responseTime,
+		RedirectChain: redirects.chain,
+		FinalURL:      resp.Request.URL.String(),
+		Timing:        timer.finish(time.Now()),
+	}
+
 	// Determine status based on HTTP status code
-	status := "up"
-	if resp.StatusCode >= 400 {
-		status = "down"
+	if monitor.ExpectedStatusCodes != "" {
+		accepted, err := models.ParseStatusCodes(monitor.ExpectedStatusCodes)
+		if err != nil {
+			metric.Status = "down"
+			metric.Error = "invalid expected status codes: " + err.Error()
+		} else if !accepted.Contains(resp.StatusCode) {
+			metric.Status = "down"
+			metric.Error = fmt.Sprintf("unexpected status code %d (expected %s)", resp.StatusCode, monitor.ExpectedStatusCodes)
+		}
+	} else if resp.StatusCode >= 400 {
+		metric.Status = "down"
+	}
+
+	// Check where the redirects ended up
+	if metric.Status == "up" && monitor.ExpectedFinalHost != "" && !strings.EqualFold(resp.Request.URL.Hostname(), monitor.ExpectedFinalHost) {
+		metric.Status = "down"
+		metric.Error = fmt.Sprintf("redirected to unexpected host %s (expected %s)", resp.Request.URL.Hostname(), monitor.ExpectedFinalHost)
+	}
+
+	// Check the response content
+	if metric.Status == "up" && (len(monitor.BodyAssertions) > 0 || len(monitor.JSONAssertions) > 0) {
+		if readErr != nil {
+			metric.Status = "down"
+			metric.Error = "failed to read response body: " + readErr.Error()
+		} else if err := checkBodyAssertions(monitor.BodyAssertions, body[:min(len(body), maxBodyBytes)]); err != nil {
+			metric.Status = "down"
+			metric.Error = err.Error()
+		} else if len(monitor.JSONAssertions) > 0 && truncated {
+			metric.Status = "down"
+			metric.Error = fmt.Sprintf("JSON assertion failed: response is larger than %d KB", maxJSONBodyBytes/1024)
+		} else if len(monitor.JSONAssertions) > 0 {
+			results, err := checkJSONAssertions(monitor.JSONAssertions, body)
+			metric.AssertionResults = results
+			if err != nil {
+				metric.Status = "down"
+				metric.Error = err.Error()
+			}
+		}
+	}
+
+	// Check the certificate for HTTPS endpoints
+	if resp.TLS != nil {
+		metric.TLS = tlsInfoFromState(resp.TLS, resp.Request.URL.Hostname())
+		applyTLSStatus(&metric, monitor)
 	}
 
-	ms.recordMetric(monitor, status, resp.StatusCode, responseTime, "", wsHub)
+	return metric
 }
 
 // recordMetric saves a metric to the database and broadcasts via WebSocket
-func (ms *MonitorService) recordMetric(monitor models.Monitor, status string, statusCode int, responseTime int64, errorMsg string, wsHub *WebSocketHub) {
+func (ms *MonitorService) recordMetric(monitor models.Monitor, metric models.Metric, wsHub *WebSocketHub) {
 	now := time.Now()
 
-	// Create metric record
-	metric := models.Metric{
-		MonitorID:    monitor.ID,
-		URL:          monitor.URL,
-		Status:       status,
-		StatusCode:   statusCode,
-		ResponseTime: responseTime,
-		Error:        errorMsg,
-		CheckedAt:    now,
+	// Complete the metric record
+	metric.MonitorID = monitor.ID
+	metric.URL = monitor.URL
+	metric.CheckedAt = now
+	metric.ExpiresAt = now.Add(monitor.MetricsRetention(ms.retention))
+	applyLatencyThresholds(monitor, &metric)
+
+	// Checks during a maintenance window are kept, but the monitor is shown
+	// as under maintenance and no incidents or alerts are raised
+	currentStatus := metric.Status
+	window := ms.maintenance.ActiveWindow(monitor, now)
+	if window != nil {
+		metric.InMaintenance = true
+		currentStatus = "maintenance"
 	}
 
+	status := metric.Status
+	statusCode := metric.StatusCode
+	responseTime := metric.ResponseTime
+	errorMsg := metric.Error
+
 	// Save to database
-	collection := ms.db.GetCollection(database.MetricsCollection)
-	_, err := collection.InsertOne(context.Background(), metric)
-	if err != nil {
+	if err := ms.store.Metrics().Insert(&metric); err != nil {
 		log.Printf("Error saving metric: %v", err)
 	}
 
-	// Update monitor's current status
-	ms.updateMonitorStatus(monitor.ID, status, statusCode, responseTime, now)
+	// Update monitor's current status, then track incidents and alerts
+	previousStatus := ms.updateMonitorStatus(monitor.ID, currentStatus, statusCode, responseTime, now)
+	if window != nil {
+		if previousStatus != "maintenance" {
+			log.Printf("🛠️  Maintenance started: %s (%s) - %s", monitor.Name, monitor.URL, window.Name)
+		}
+	} else {
+		ms.handleTransition(monitor, previousStatus, metric, wsHub)
+	}
+	if metric.TLS != nil {
+		ms.updateMonitorCertificate(monitor.ID, metric.TLS)
+	}
+
+	// Status changes move the dashboard counters
+	if currentStatus != previousStatus {
+		ms.broadcastDashboardStats(wsHub)
+	}
 
 	// Calculate uptime percentage
 	uptimePercentage := ms.calculateUptimePercentage(monitor.ID)
@@ -282,6 +631,10 @@ func (ms *MonitorService) recordMetric(monitor models.Monitor, status string, st
 		ResponseTime:     responseTime,
 		URL:              monitor.URL,
 		Error:            errorMsg,
+		TLS:              metric.TLS,
+		AssertionResults: metric.AssertionResults,
+		Timing:           metric.Timing,
+		InMaintenance:    metric.InMaintenance,
 		Timestamp:        now,
 		UptimePercentage: uptimePercentage,
 	}
@@ -293,72 +646,145 @@ func (ms *MonitorService) recordMetric(monitor models.Monitor, status string, st
 	}
 
 	// Log status changes
-	if status == "down" {
+	switch {
+	case metric.InMaintenance:
+		log.Printf("🛠️  MAINTENANCE: %s (%s) - %s - %dms", monitor.Name, monitor.URL, status, responseTime)
+	case status == "down":
 		log.Printf("🔴 DOWN: %s (%s) - %dms - %s", monitor.Name, monitor.URL, responseTime, errorMsg)
-	} else {
+	case status == "degraded":
+		log.Printf("🟡 DEGRADED: %s (%s) - %dms - %s", monitor.Name, monitor.URL, responseTime, errorMsg)
+	case monitor.Type == "tcp":
+		log.Printf("🟢 UP: %s (%s) - %dms - TCP connect", monitor.Name, monitor.URL, responseTime)
+	case monitor.Type == "dns":
+		log.Printf("🟢 UP: %s (%s) - %dms - DNS %s", monitor.Name, monitor.URL, responseTime, monitor.DNSRecordType)
+	default:
 		log.Printf("🟢 UP: %s (%s) - %dms - HTTP %d", monitor.Name, monitor.URL, responseTime, statusCode)
 	}
 }
 
-// updateMonitorStatus updates the monitor's current status in the database
-func (ms *MonitorService) updateMonitorStatus(monitorID primitive.ObjectID, status string, statusCode int, responseTime int64, lastChecked time.Time) {
-	collection := ms.db.GetCollection(database.MonitorsCollection)
-	
-	update := bson.M{
-		"$set": bson.M{
-			"current_status":   status,
-			"current_status_code": statusCode,
-			"current_response": responseTime,
-			"last_checked":     lastChecked,
-			"updated_at":       time.Now(),
-		},
+// handleTransition opens or resolves incidents for a check and alerts
+// notification channels about status changes, replacing individual alerts
+// with a single event while the monitor is flapping
+func (ms *MonitorService) handleTransition(monitor models.Monitor, previousStatus string, metric models.Metric, wsHub *WebSocketHub) {
+	incident := ms.incidents.HandleCheck(monitor, previousStatus, metric, wsHub)
+
+	flap := ms.flaps.Record(monitor.ID.Hex(), metric.Status)
+	switch {
+	case flap.Changed:
+		ms.handleFlappingChange(monitor, metric, flap, wsHub)
+	case flap.Flapping:
+		// Suppressed until the monitor settles
+	case monitor.EscalationPolicyID != nil:
+		ms.escalations.HandleTransition(monitor, previousStatus, metric, incident)
+	default:
+		ms.notifications.NotifyTransition(monitor, previousStatus, metric, incident)
+	}
+}
+
+// handleFlappingChange records that a monitor started or stopped flapping
+// and announces it to dashboards and notification channels
+func (ms *MonitorService) handleFlappingChange(monitor models.Monitor, metric models.Metric, flap FlapResult, wsHub *WebSocketHub) {
+	if err := ms.store.Monitors().SetFlapping(monitor.ID, flap.Flapping); err != nil {
+		log.Printf("Error updating monitor flapping state: %v", err)
 	}
 
-	_, err := collection.UpdateOne(context.Background(), bson.M{"_id": monitorID}, update)
-	if err != nil {
-		log.Printf("Error updating monitor status: %v", err)
+	messageType := "flapping_stopped"
+	if flap.Flapping {
+		messageType = "flapping_started"
+		log.Printf("🔁 FLAPPING: %s (%s) - %.0f%% of recent checks changed state", monitor.Name, monitor.URL, flap.ChangeRate*100)
+	} else {
+		log.Printf("✅ STABLE: %s (%s) stopped flapping, now %s", monitor.Name, monitor.URL, metric.Status)
+	}
+
+	wsHub.Broadcast <- models.WebSocketMessage{
+		Type: messageType,
+		Data: models.FlappingUpdate{
+			MonitorID:  monitor.ID.Hex(),
+			IsFlapping: flap.Flapping,
+			Status:     metric.Status,
+			ChangeRate: flap.ChangeRate,
+			Timestamp:  metric.CheckedAt,
+		},
+		MonitorID: monitor.ID.Hex(),
 	}
+
+	ms.escalations.Route(monitor, models.NotificationEvent{
+		Type:         "monitor_" + messageType,
+		MonitorID:    monitor.ID.Hex(),
+		MonitorName:  monitor.Name,
+		URL:          monitor.URL,
+		Status:       metric.Status,
+		StatusCode:   metric.StatusCode,
+		ResponseTime: metric.ResponseTime,
+		Error:        metric.Error,
+		Timestamp:    metric.CheckedAt,
+	})
 }
 
-// calculateUptimePercentage calculates uptime percentage for the last 24 hours
-// calculateUptimePercentage calculates uptime percentage for the last 24 hours
-func (ms *MonitorService) calculateUptimePercentage(monitorID primitive.ObjectID) float64 {
-	collection := ms.db.GetCollection(database.MetricsCollection)
+// applyLatencyThresholds marks a check that reached the endpoint as degraded
+// or down when its response time exceeds the monitor's thresholds
+func applyLatencyThresholds(monitor models.Monitor, metric *models.Metric) {
+	if metric.Status == "down" {
+		return
+	}
 
-	startTime := time.Now().Add(-24 * time.Hour)
-	filter := bson.M{
-		"monitor_id": monitorID,
-		"checked_at": bson.M{"$gte": startTime},
+	var reason string
+	switch {
+	case monitor.CriticalThreshold > 0 && metric.ResponseTime >= int64(monitor.CriticalThreshold):
+		metric.Status = "down"
+		reason = fmt.Sprintf("response time %dms exceeded critical threshold of %dms", metric.ResponseTime, monitor.CriticalThreshold)
+	case monitor.WarningThreshold > 0 && metric.ResponseTime >= int64(monitor.WarningThreshold):
+		metric.Status = "degraded"
+		reason = fmt.Sprintf("response time %dms exceeded warning threshold of %dms", metric.ResponseTime, monitor.WarningThreshold)
+	default:
+		return
+	}
+
+	if metric.Error != "" {
+		metric.Error += "; " + reason
+	} else {
+		metric.Error = reason
 	}
+}
 
-	cursor, err := collection.Find(context.Background(), filter)
+// updateMonitorStatus updates the monitor's current status in the database
+// and returns the status it had before the update
+func (ms *MonitorService) updateMonitorStatus(monitorID primitive.ObjectID, status string, statusCode int, responseTime int64, lastChecked time.Time) string {
+	previous, err := ms.store.Monitors().UpdateStatus(monitorID, status, statusCode, responseTime, lastChecked)
 	if err != nil {
-		log.Printf("Error fetching metrics for uptime calculation: %v", err)
-		return 100 // fail-open: assume 100% uptime if query fails
+		log.Printf("Error updating monitor status: %v", err)
+		return ""
 	}
-	defer cursor.Close(context.Background())
 
-	total := 0
-	down := 0
+	return previous
+}
 
-	for cursor.Next(context.Background()) {
-		var metric models.Metric
-		if err := cursor.Decode(&metric); err == nil {
-			total++
-			if metric.Status == "down" {
-				down++
-			}
-		}
+// updateMonitorCertificate stores the certificate seen by the latest check on the monitor
+func (ms *MonitorService) updateMonitorCertificate(monitorID primitive.ObjectID, info *models.TLSInfo) {
+	if err := ms.store.Monitors().UpdateCertificate(monitorID, info); err != nil {
+		log.Printf("Error updating monitor certificate: %v", err)
+	}
+}
+
+// calculateUptimePercentage calculates uptime percentage for the last 24
+// hours. Planned downtime does not count against uptime.
+func (ms *MonitorService) calculateUptimePercentage(monitorID primitive.ObjectID) float64 {
+	startTime := time.Now().Add(-24 * time.Hour)
+	counts, err := ms.store.Metrics().CountSince(monitorID, startTime)
+	if err != nil {
+		log.Printf("Error counting metrics for uptime calculation: %v", err)
+		return 100 // fail-open: assume 100% uptime if query fails
 	}
 
-	if total == 0 {
+	if counts.Total == 0 {
 		return 100
 	}
 
-	uptime := (float64(total-down) / float64(total)) * 100
+	uptime := (float64(counts.Total-counts.Down) / float64(counts.Total)) * 100
 	return uptime
 }
+
 // StartMonitorJob starts a monitoring job for a specific monitor (public method)
 func (ms *MonitorService) StartMonitorJob(monitor models.Monitor, wsHub *WebSocketHub) {
 	ms.startMonitorJob(monitor, wsHub)
-}
\ No newline at end of file
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/config"
+	"monitoring-tool/database"
+	"monitoring-tool/models"
+)
+
+// testServices wires the services to an in-memory store
+type testServices struct {
+	store     *database.MemoryStore
+	monitors  *MonitorService
+	incidents *IncidentService
+	hub       *WebSocketHub
+}
+
+func newTestServices(t *testing.T) *testServices {
+	t.Helper()
+
+	store := database.NewMemoryStore(24 * time.Hour)
+	incidents := NewIncidentService(store)
+	notifications := NewNotificationService(store, &config.Config{})
+	maintenance := NewMaintenanceService(store)
+	escalations := NewEscalationService(store, incidents, notifications, maintenance)
+	flaps := NewFlapDetector(20, 50, 25)
+
+	// Nobody is connected, so broadcasts are dropped
+	hub := NewWebSocketHub()
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-hub.Broadcast:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	s := &testServices{
+		store:     store,
+		monitors:  NewMonitorService(store, incidents, notifications, escalations, flaps, maintenance, 10, 24*time.Hour),
+		incidents: incidents,
+		hub:       hub,
+	}
+	t.Cleanup(func() {
+		s.monitors.jobsMutex.Lock()
+		for id, stop := range s.monitors.activeJobs {
+			close(stop)
+			delete(s.monitors.activeJobs, id)
+		}
+		s.monitors.jobsMutex.Unlock()
+		close(done)
+	})
+	return s
+}
+
+// createMonitor creates a monitor from a request with the API defaults
+func (s *testServices) createMonitor(t *testing.T, req models.CreateMonitorRequest) *models.Monitor {
+	t.Helper()
+
+	monitor := req.ToMonitor()
+	if err := s.monitors.CreateMonitor(monitor); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	return monitor
+}
+
+// running reports whether the monitor has a monitoring job
+func (s *testServices) running(id primitive.ObjectID) bool {
+	s.monitors.jobsMutex.RLock()
+	defer s.monitors.jobsMutex.RUnlock()
+	_, ok := s.monitors.activeJobs[id.Hex()]
+	return ok
+}
+
+func TestMonitorLifecycle(t *testing.T) {
+	s := newTestServices(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	monitor := s.createMonitor(t, models.CreateMonitorRequest{Name: "API", URL: server.URL})
+	if monitor.ID.IsZero() || !monitor.IsActive || monitor.Interval != 30 || monitor.Method != "GET" {
+		t.Fatalf("created monitor = %+v, want an active GET monitor checked every 30s", monitor)
+	}
+
+	// Same target
+	duplicate := models.CreateMonitorRequest{Name: "Copy", URL: server.URL}
+	if err := s.monitors.CreateMonitor(duplicate.ToMonitor()); err == nil || !strings.HasSuffix(err.Error(), "already exists") {
+		t.Fatalf("duplicate create err = %v, want already exists", err)
+	}
+
+	// Update
+	name, interval := "Public API", 60
+	updated, err := s.monitors.UpdateMonitor(monitor.ID, &models.UpdateMonitorRequest{Name: &name, Interval: &interval}, s.hub)
+	if err != nil {
+		t.Fatalf("UpdateMonitor failed: %v", err)
+	}
+	if updated.Name != name || updated.Interval != interval || updated.URL != server.URL {
+		t.Fatalf("updated monitor = %+v, want name and interval changed only", updated)
+	}
+
+	invalid := -1
+	if _, err := s.monitors.UpdateMonitor(monitor.ID, &models.UpdateMonitorRequest{Interval: &invalid}, s.hub); !errors.Is(err, models.ErrInvalidMonitor) {
+		t.Fatalf("invalid update err = %v, want ErrInvalidMonitor", err)
+	}
+	stored, err := s.monitors.GetMonitor(monitor.ID)
+	if err != nil || stored.Interval != interval {
+		t.Fatalf("stored monitor after rejected update = %+v, %v, want interval %d", stored, err, interval)
+	}
+
+	// Pause
+	paused, err := s.monitors.PauseMonitor(monitor.ID, &models.PauseMonitorRequest{PausedBy: "alice", Reason: "deploy"}, s.hub)
+	if err != nil {
+		t.Fatalf("PauseMonitor failed: %v", err)
+	}
+	if paused.IsActive || paused.Status != "paused" || paused.PausedBy != "alice" || paused.PauseReason != "deploy" || paused.PausedAt == nil {
+		t.Fatalf("paused monitor = %+v", paused)
+	}
+	if s.running(monitor.ID) {
+		t.Error("paused monitor still has a running job")
+	}
+
+	// Resume
+	resumed, err := s.monitors.ResumeMonitor(monitor.ID, s.hub)
+	if err != nil {
+		t.Fatalf("ResumeMonitor failed: %v", err)
+	}
+	if !resumed.IsActive || resumed.Status != "active" || resumed.PausedAt != nil || resumed.PausedBy != "" {
+		t.Fatalf("resumed monitor = %+v", resumed)
+	}
+	if !s.running(monitor.ID) {
+		t.Error("resumed monitor has no running job")
+	}
+
+	// Delete
+	if err := s.monitors.DeleteMonitor(monitor.ID); err != nil {
+		t.Fatalf("DeleteMonitor failed: %v", err)
+	}
+	if _, err := s.monitors.GetMonitor(monitor.ID); err == nil || err.Error() != "monitor not found" {
+		t.Fatalf("GetMonitor after delete err = %v, want monitor not found", err)
+	}
+	if _, err := s.monitors.PauseMonitor(monitor.ID, &models.PauseMonitorRequest{}, s.hub); err == nil || err.Error() != "monitor not found" {
+		t.Fatalf("PauseMonitor after delete err = %v, want monitor not found", err)
+	}
+}
+
+func TestIncidentLifecycle(t *testing.T) {
+	s := newTestServices(t)
+	monitor := s.createMonitor(t, models.CreateMonitorRequest{Name: "API", URL: "https://api.example.com"})
+
+	start := time.Now().Add(-time.Minute)
+	opened := s.incidents.HandleCheck(*monitor, "up", models.Metric{Status: "down", Error: "timeout", CheckedAt: start}, s.hub)
+	if opened == nil || opened.Status != "open" || opened.AffectedChecks != 1 || opened.FirstError != "timeout" {
+		t.Fatalf("opened incident = %+v", opened)
+	}
+
+	// A further failure counts towards the open incident
+	if again := s.incidents.HandleCheck(*monitor, "down", models.Metric{Status: "down", Error: "refused", CheckedAt: start.Add(30 * time.Second)}, s.hub); again != nil {
+		t.Fatalf("second failure returned %+v, want nil", again)
+	}
+	incident, err := s.incidents.GetIncident(opened.ID)
+	if err != nil || incident.AffectedChecks != 2 || incident.LastError != "refused" {
+		t.Fatalf("incident after second failure = %+v, %v", incident, err)
+	}
+
+	// Checks that stay up leave it alone
+	if other := s.incidents.HandleCheck(*monitor, "up", models.Metric{Status: "up", CheckedAt: start}, s.hub); other != nil {
+		t.Fatalf("up check without previous failure returned %+v", other)
+	}
+
+	resolved := s.incidents.HandleCheck(*monitor, "down", models.Metric{Status: "up", CheckedAt: start.Add(time.Minute)}, s.hub)
+	if resolved == nil || resolved.Status != "resolved" || resolved.Duration != 60 || resolved.ResolvedAt == nil {
+		t.Fatalf("resolved incident = %+v, want resolved after 60s", resolved)
+	}
+
+	// The next failure opens a new incident
+	next := s.incidents.HandleCheck(*monitor, "up", models.Metric{Status: "down", CheckedAt: time.Now()}, s.hub)
+	if next == nil || next.ID == opened.ID {
+		t.Fatalf("failure after recovery returned %+v, want a new incident", next)
+	}
+
+	incidents, err := s.incidents.GetMonitorIncidents(monitor.ID, 0)
+	if err != nil || len(incidents) != 2 || incidents[0].ID != next.ID {
+		t.Fatalf("monitor incidents = %+v, %v, want two, newest first", incidents, err)
+	}
+	open, err := s.incidents.GetIncidents("open", 0)
+	if err != nil || len(open) != 1 || open[0].ID != next.ID {
+		t.Fatalf("open incidents = %+v, %v, want only the new one", open, err)
+	}
+}
+
+func TestPauseResolvesOpenIncident(t *testing.T) {
+	s := newTestServices(t)
+	monitor := s.createMonitor(t, models.CreateMonitorRequest{Name: "API", URL: "https://api.example.com"})
+
+	opened := s.incidents.HandleCheck(*monitor, "up", models.Metric{Status: "down", CheckedAt: time.Now()}, s.hub)
+	if opened == nil {
+		t.Fatal("failed check opened no incident")
+	}
+
+	if _, err := s.monitors.PauseMonitor(monitor.ID, &models.PauseMonitorRequest{}, s.hub); err != nil {
+		t.Fatalf("PauseMonitor failed: %v", err)
+	}
+	incident, err := s.incidents.GetIncident(opened.ID)
+	if err != nil || incident.Status != "resolved" {
+		t.Fatalf("incident after pause = %+v, %v, want resolved", incident, err)
+	}
+}
+
+func TestAcknowledgeIncident(t *testing.T) {
+	s := newTestServices(t)
+	monitor := s.createMonitor(t, models.CreateMonitorRequest{Name: "API", URL: "https://api.example.com"})
+	policy := &models.EscalationPolicy{
+		Name:  "On call",
+		Steps: []models.EscalationStep{{ChannelIDs: []primitive.ObjectID{primitive.NewObjectID()}}},
+	}
+	if err := s.store.EscalationPolicies().Create(policy); err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+	monitor.EscalationPolicyID = &policy.ID
+
+	opened := s.incidents.HandleCheck(*monitor, "up", models.Metric{Status: "down", CheckedAt: time.Now()}, s.hub)
+	if opened == nil {
+		t.Fatal("failed check opened no incident")
+	}
+	escalating, err := s.incidents.GetEscalatingIncidents()
+	if err != nil || len(escalating) != 1 {
+		t.Fatalf("escalating incidents = %+v, %v, want the open one", escalating, err)
+	}
+
+	acknowledged, err := s.incidents.AcknowledgeIncident(opened.ID, "alice", s.hub)
+	if err != nil {
+		t.Fatalf("AcknowledgeIncident failed: %v", err)
+	}
+	if acknowledged.AcknowledgedBy != "alice" || acknowledged.AcknowledgedAt == nil || acknowledged.Status != "open" {
+		t.Fatalf("acknowledged incident = %+v", acknowledged)
+	}
+
+	// Acknowledged incidents no longer escalate
+	escalating, err = s.incidents.GetEscalatingIncidents()
+	if err != nil || len(escalating) != 0 {
+		t.Fatalf("escalating incidents after acknowledgement = %+v, %v, want none", escalating, err)
+	}
+	if advanced, err := s.incidents.AdvanceEscalation(opened.ID, 0, 1); err != nil || advanced {
+		t.Fatalf("AdvanceEscalation after acknowledgement = %v, %v, want false", advanced, err)
+	}
+
+	if _, err := s.incidents.AcknowledgeIncident(opened.ID, "bob", s.hub); err == nil || err.Error() != "incident is already acknowledged" {
+		t.Fatalf("second acknowledgement err = %v, want already acknowledged", err)
+	}
+
+	s.incidents.ResolveMonitorIncidents(monitor.ID, s.hub)
+	if _, err := s.incidents.AcknowledgeIncident(opened.ID, "bob", s.hub); err == nil || err.Error() != "incident is already resolved" {
+		t.Fatalf("acknowledging a resolved incident err = %v, want already resolved", err)
+	}
+
+	if _, err := s.incidents.AcknowledgeIncident(primitive.NewObjectID(), "bob", s.hub); err == nil || err.Error() != "incident not found" {
+		t.Fatalf("acknowledging a missing incident err = %v, want incident not found", err)
+	}
+}
+
+func TestCalculateUptimePercentage(t *testing.T) {
+	s := newTestServices(t)
+	monitor := s.createMonitor(t, models.CreateMonitorRequest{Name: "API", URL: "https://api.example.com"})
+
+	if uptime := s.monitors.calculateUptimePercentage(monitor.ID); uptime != 100 {
+		t.Errorf("uptime without checks = %v, want 100", uptime)
+	}
+
+	// Three up or degraded checks and one down; maintenance and checks
+	// older than a day are left out. Checks are saved in the order made.
+	now := time.Now()
+	for _, metric := range []models.Metric{
+		{Status: "down", CheckedAt: now.Add(-25 * time.Hour)},
+		{Status: "up", CheckedAt: now.Add(-time.Hour)},
+		{Status: "degraded", CheckedAt: now.Add(-time.Hour)},
+		{Status: "up", CheckedAt: now.Add(-time.Hour)},
+		{Status: "down", CheckedAt: now.Add(-time.Hour)},
+		{Status: "down", CheckedAt: now.Add(-time.Hour), InMaintenance: true},
+	} {
+		metric.MonitorID = monitor.ID
+		if err := s.store.Metrics().Insert(&metric); err != nil {
+			t.Fatalf("failed to insert metric: %v", err)
+		}
+	}
+
+	if uptime := s.monitors.calculateUptimePercentage(monitor.ID); uptime != 75 {
+		t.Errorf("uptime = %v, want 75", uptime)
+	}
+}
+
+func TestUpdateMonitorKeepsRedactedSecrets(t *testing.T) {
+	s := newTestServices(t)
+	monitor := s.createMonitor(t, models.CreateMonitorRequest{
+		Name:    "API",
+		URL:     "https://api.example.com",
+		Headers: map[string]string{"X-API-Key": "k3y-value", "Accept": "application/json"},
+		Auth:    &models.MonitorAuth{Type: "bearer", Token: "bearer-t0ken"},
+	})
+	// Paused so the update does not restart checks of the example host
+	if _, err := s.monitors.PauseMonitor(monitor.ID, &models.PauseMonitorRequest{}, s.hub); err != nil {
+		t.Fatalf("PauseMonitor failed: %v", err)
+	}
+
+	// PATCH with the monitor as read from the API, renamed
+	read, err := s.monitors.GetMonitor(monitor.ID)
+	if err != nil {
+		t.Fatalf("GetMonitor failed: %v", err)
+	}
+	encoded, err := json.Marshal(read)
+	if err != nil {
+		t.Fatalf("failed to encode monitor: %v", err)
+	}
+	if strings.Contains(string(encoded), "k3y-value") || strings.Contains(string(encoded), "bearer-t0ken") {
+		t.Fatalf("API response exposes secrets: %s", encoded)
+	}
+	var req models.UpdateMonitorRequest
+	if err := json.Unmarshal(encoded, &req); err != nil {
+		t.Fatalf("failed to decode request: %v", err)
+	}
+	name := "Renamed API"
+	req.Name = &name
+
+	if _, err := s.monitors.UpdateMonitor(monitor.ID, &req, s.hub); err != nil {
+		t.Fatalf("UpdateMonitor failed: %v", err)
+	}
+	stored, err := s.store.Monitors().Get(monitor.ID)
+	if err != nil {
+		t.Fatalf("failed to load monitor: %v", err)
+	}
+	if stored.Name != name || stored.Headers["X-API-Key"] != "k3y-value" || stored.Headers["Accept"] != "application/json" || stored.Auth == nil || stored.Auth.Token != "bearer-t0ken" {
+		t.Fatalf("stored monitor after PATCH = %+v, auth %+v, want the secrets kept", stored, stored.Auth)
+	}
+}
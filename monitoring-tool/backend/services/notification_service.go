@@ -0,0 +1,395 @@
+// services/notification_service.go
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/config"
+	"monitoring-tool/database"
+	"monitoring-tool/models"
+)
+
+const (
+	// maxDeliveryAttempts is how many times an event is sent before giving up
+	maxDeliveryAttempts = 5
+	// initialDeliveryBackoff is the wait after the first failed attempt; it doubles after each failure
+	initialDeliveryBackoff = 2 * time.Second
+	// deliveryTimeout bounds a single delivery attempt
+	deliveryTimeout = 10 * time.Second
+)
+
+// NotificationService delivers monitor alerts to notification channels
+type NotificationService struct {
+	store      database.Store
+	cfg        *config.Config
+	httpClient *http.Client
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(store database.Store, cfg *config.Config) *NotificationService {
+	return &NotificationService{
+		store: store,
+		cfg:   cfg,
+		httpClient: &http.Client{
+			Timeout: deliveryTimeout,
+		},
+	}
+}
+
+// NotifyTransition sends an event to the monitor's channels when a check
+// changes its status
+func (ns *NotificationService) NotifyTransition(monitor models.Monitor, previousStatus string, metric models.Metric, incident *models.Incident) {
+	if event := TransitionEvent(monitor, previousStatus, metric, incident); event != nil {
+		ns.Notify(*event, monitor.NotificationChannelIDs)
+	}
+}
+
+// TransitionEvent builds the event for a check that changed the monitor's
+// status, or returns nil when there is nothing to alert on. The first
+// successful check of a new monitor, or after maintenance, is not a
+// transition worth alerting on.
+func TransitionEvent(monitor models.Monitor, previousStatus string, metric models.Metric, incident *models.Incident) *models.NotificationEvent {
+	if metric.Status == previousStatus || previousStatus == "" {
+		return nil
+	}
+	if (previousStatus == "unknown" || previousStatus == "maintenance") && metric.Status == "up" && incident == nil {
+		return nil
+	}
+
+	event := models.NotificationEvent{
+		Type:           "monitor_" + metric.Status,
+		MonitorID:      monitor.ID.Hex(),
+		MonitorName:    monitor.Name,
+		URL:            monitor.URL,
+		Status:         metric.Status,
+		PreviousStatus: previousStatus,
+		StatusCode:     metric.StatusCode,
+		ResponseTime:   metric.ResponseTime,
+		Error:          metric.Error,
+		Timestamp:      metric.CheckedAt,
+	}
+	if incident != nil {
+		event.IncidentID = incident.ID.Hex()
+		event.IncidentDuration = incident.Duration
+	}
+
+	return &event
+}
+
+// Notify delivers an event in the background to the given channels, or to
+// every enabled channel when channelIDs is empty
+func (ns *NotificationService) Notify(event models.NotificationEvent, channelIDs []primitive.ObjectID) {
+	channels, err := ns.GetChannels()
+	if err != nil {
+		log.Printf("Error loading notification channels: %v", err)
+		return
+	}
+
+	selected := make(map[primitive.ObjectID]bool, len(channelIDs))
+	for _, id := range channelIDs {
+		selected[id] = true
+	}
+
+	for _, channel := range channels {
+		if !channel.Enabled || (len(selected) > 0 && !selected[channel.ID]) {
+			continue
+		}
+		go ns.deliver(channel, event, maxDeliveryAttempts)
+	}
+}
+
+// deliver sends an event to a channel, making up to maxAttempts attempts with
+// exponential backoff, and records the outcome in the delivery log
+func (ns *NotificationService) deliver(channel models.NotificationChannel, event models.NotificationEvent, maxAttempts int) models.NotificationDelivery {
+	delivery := models.NotificationDelivery{
+		ChannelID:   channel.ID,
+		ChannelName: channel.Name,
+		EventType:   event.Type,
+		MonitorID:   event.MonitorID,
+		Status:      "failed",
+		CreatedAt:   time.Now(),
+	}
+
+	backoff := initialDeliveryBackoff
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		statusCode, err := ns.send(channel, event)
+
+		result := models.DeliveryAttempt{Attempt: attempt, StatusCode: statusCode, SentAt: time.Now()}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		delivery.Attempts = append(delivery.Attempts, result)
+
+		if err == nil {
+			delivery.Status = "delivered"
+			break
+		}
+
+		log.Printf("⚠️  Notification to %s failed (attempt %d/%d): %v", channel.Name, attempt, maxAttempts, err)
+		if attempt < maxAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	delivery.CompletedAt = time.Now()
+
+	if err := ns.store.Notifications().InsertDelivery(&delivery); err != nil {
+		log.Printf("Error saving notification delivery: %v", err)
+	}
+
+	return delivery
+}
+
+// send makes a single delivery attempt and returns the HTTP status code received
+func (ns *NotificationService) send(channel models.NotificationChannel, event models.NotificationEvent) (int, error) {
+	switch channel.Type {
+	case "webhook":
+		return ns.sendWebhook(channel, event)
+	case "email":
+		return 0, ns.sendEmail(channel, event)
+	case "slack", "discord", "teams", "mattermost":
+		return ns.sendChat(channel, event)
+	default:
+		return 0, fmt.Errorf("unsupported channel type: %s", channel.Type)
+	}
+}
+
+// sendWebhook posts the event as JSON, signing the body when a secret is set
+func (ns *NotificationService) sendWebhook(channel models.NotificationChannel, event models.NotificationEvent) (int, error) {
+	body, err := renderWebhookBody(channel, event)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, channel.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "RealtimeMonitor/1.0")
+	req.Header.Set("X-Monitor-Event", event.Type)
+	for name, value := range channel.Headers {
+		req.Header.Set(name, value)
+	}
+	if channel.Secret != "" {
+		req.Header.Set("X-Monitor-Signature", "sha256="+signPayload(channel.Secret, body))
+	}
+
+	return ns.post(req)
+}
+
+// post performs a delivery request; any non-2xx response is a failure.
+// Transport errors are returned without the request URL, which for chat
+// channels is a secret, as they end up in the delivery log.
+func (ns *NotificationService) post(req *http.Request) (int, error) {
+	resp, err := ns.httpClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return 0, urlErr.Err
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// renderWebhookBody builds the JSON body from the channel template, or
+// encodes the event itself when the channel has no template
+func renderWebhookBody(channel models.NotificationChannel, event models.NotificationEvent) ([]byte, error) {
+	if channel.Template == "" {
+		return json.Marshal(event)
+	}
+
+	tmpl, err := template.New("webhook").Funcs(models.TemplateFuncs).Parse(channel.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return nil, fmt.Errorf("failed to render template: %v", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("template did not render valid JSON")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// signPayload returns the hex encoded HMAC-SHA256 of the body
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateChannel adds a new notification channel
+func (ns *NotificationService) CreateChannel(channel *models.NotificationChannel) error {
+	if err := channel.Validate(); err != nil {
+		return err
+	}
+	if channel.Type == "email" && ns.cfg.SMTPHost == "" {
+		return fmt.Errorf("%w: email channels require SMTP_HOST to be configured", models.ErrInvalidChannel)
+	}
+
+	if err := ns.store.Notifications().CreateChannel(channel); err != nil {
+		return err
+	}
+
+	log.Printf("✅ Created notification channel: %s (%s)", channel.Name, channel.Type)
+
+	return nil
+}
+
+// GetChannels retrieves all notification channels
+func (ns *NotificationService) GetChannels() ([]models.NotificationChannel, error) {
+	return ns.store.Notifications().ListChannels()
+}
+
+// CheckChannelIDs verifies that every ID refers to an existing channel
+func (ns *NotificationService) CheckChannelIDs(ids []primitive.ObjectID) error {
+	found, err := ns.channelsExist(ids)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%w: unknown notification channel in notification_channel_ids", models.ErrInvalidMonitor)
+	}
+
+	return nil
+}
+
+// channelsExist reports whether every ID refers to an existing channel
+func (ns *NotificationService) channelsExist(ids []primitive.ObjectID) (bool, error) {
+	if len(ids) == 0 {
+		return true, nil
+	}
+
+	unique := map[primitive.ObjectID]bool{}
+	for _, id := range ids {
+		unique[id] = true
+	}
+
+	count, err := ns.store.Notifications().CountChannels(ids)
+	if err != nil {
+		return false, err
+	}
+
+	return count == len(unique), nil
+}
+
+// GetChannel retrieves a single notification channel by ID
+func (ns *NotificationService) GetChannel(id primitive.ObjectID) (*models.NotificationChannel, error) {
+	channel, err := ns.store.Notifications().GetChannel(id)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("channel not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// DeleteChannel removes a notification channel. A channel still named by a
+// monitor or an escalation policy step is not removed, since its alerts
+// would otherwise be dropped without notice.
+func (ns *NotificationService) DeleteChannel(id primitive.ObjectID) error {
+	users, err := ns.channelUsers(id)
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		return fmt.Errorf("channel is still used by %s", strings.Join(users, ", "))
+	}
+
+	err = ns.store.Notifications().DeleteChannel(id)
+	if errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("channel not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("🗑️  Deleted notification channel: %s", id.Hex())
+	return nil
+}
+
+// channelUsers describes the monitors and escalation policies that name a channel
+func (ns *NotificationService) channelUsers(id primitive.ObjectID) ([]string, error) {
+	var users []string
+
+	monitors, err := ns.store.Monitors().List()
+	if err != nil {
+		return nil, err
+	}
+	for _, monitor := range monitors {
+		for _, channelID := range monitor.NotificationChannelIDs {
+			if channelID == id {
+				users = append(users, fmt.Sprintf("monitor %q", monitor.Name))
+				break
+			}
+		}
+	}
+
+	policies, err := ns.store.EscalationPolicies().List()
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		for _, channelID := range policy.StepChannelIDs(len(policy.Steps)) {
+			if channelID == id {
+				users = append(users, fmt.Sprintf("escalation policy %q", policy.Name))
+				break
+			}
+		}
+	}
+
+	return users, nil
+}
+
+// TestChannel sends a test event to a channel with a single attempt and
+// returns the outcome
+func (ns *NotificationService) TestChannel(id primitive.ObjectID) (models.NotificationDelivery, error) {
+	channel, err := ns.GetChannel(id)
+	if err != nil {
+		return models.NotificationDelivery{}, err
+	}
+
+	event := models.NotificationEvent{
+		Type:        "test",
+		MonitorName: "Test notification",
+		Status:      "up",
+		Timestamp:   time.Now(),
+	}
+
+	return ns.deliver(*channel, event, 1), nil
+}
+
+// GetDeliveries retrieves the delivery log, newest first, optionally for one channel
+func (ns *NotificationService) GetDeliveries(channelID *primitive.ObjectID, limit int) ([]models.NotificationDelivery, error) {
+	return ns.store.Notifications().ListDeliveries(channelID, limit)
+}
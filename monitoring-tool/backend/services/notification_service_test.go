@@ -0,0 +1,52 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-tool/config"
+	"monitoring-tool/database"
+	"monitoring-tool/models"
+)
+
+func TestDeliveryErrorHidesChatURL(t *testing.T) {
+	store := database.NewMemoryStore(time.Hour)
+	ns := NewNotificationService(store, &config.Config{})
+
+	// Nothing listens on port 1, so the connection is refused
+	secret := "T0000/B0000/XXXXsecretXXXX"
+	for _, channelType := range []string{"slack", "discord", "teams", "mattermost"} {
+		t.Run(channelType, func(t *testing.T) {
+			channel := models.NotificationChannel{
+				Name:    channelType,
+				Type:    channelType,
+				Enabled: true,
+				URL:     "http://127.0.0.1:1/services/" + secret,
+			}
+			if err := store.Notifications().CreateChannel(&channel); err != nil {
+				t.Fatalf("failed to create channel: %v", err)
+			}
+
+			delivery := ns.deliver(channel, models.NotificationEvent{Type: "monitor_down", Status: "down", Timestamp: time.Now()}, 1)
+			if delivery.Status != "failed" || len(delivery.Attempts) != 1 || delivery.Attempts[0].Error == "" {
+				t.Fatalf("delivery = %+v, want one failed attempt with an error", delivery)
+			}
+
+			deliveries, err := store.Notifications().ListDeliveries(&channel.ID, 0)
+			if err != nil || len(deliveries) != 1 {
+				t.Fatalf("stored deliveries = %v, %v, want one", deliveries, err)
+			}
+			encoded, err := json.Marshal(deliveries)
+			if err != nil {
+				t.Fatalf("failed to encode deliveries: %v", err)
+			}
+			for _, leaked := range []string{secret, "127.0.0.1:1/services"} {
+				if strings.Contains(string(encoded), leaked) {
+					t.Errorf("delivery log %s contains %q", encoded, leaked)
+				}
+			}
+		})
+	}
+}
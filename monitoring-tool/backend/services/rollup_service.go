@@ -0,0 +1,173 @@
+// services/rollup_service.go
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/config"
+	"monitoring-tool/database"
+	"monitoring-tool/models"
+)
+
+// rollupInterval is how often closed buckets are aggregated
+const rollupInterval = time.Minute
+
+// rollupPage bounds the span of raw metrics loaded at once, so the first
+// pass does not hold the whole retention window in memory
+const rollupPage = 6 * time.Hour
+
+// rollupDelay gives checks that finished just before a bucket closed time
+// to be saved before the bucket is aggregated
+const rollupDelay = 15 * time.Second
+
+// RollupService aggregates raw metrics into 1-minute, 1-hour and 1-day
+// rollups, so long ranges can be shown after the raw checks have expired
+type RollupService struct {
+	store     database.Store
+	retention map[string]time.Duration // per resolution
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewRollupService creates a new rollup service
+func NewRollupService(store database.Store, cfg *config.Config) *RollupService {
+	return &RollupService{
+		store: store,
+		retention: map[string]time.Duration{
+			models.ResolutionMinute: time.Duration(cfg.RollupMinuteRetentionDays) * 24 * time.Hour,
+			models.ResolutionHour:   time.Duration(cfg.RollupHourRetentionDays) * 24 * time.Hour,
+			models.ResolutionDay:    time.Duration(cfg.RollupDayRetentionDays) * 24 * time.Hour,
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// ResolutionFor picks the resolution used to show the last hours of a
+// monitor checked every interval seconds: raw checks while the range holds
+// no more than maxRawMetrics of them, then the finest rollup that is kept
+// for the whole range
+func (rs *RollupService) ResolutionFor(hours, interval int) string {
+	if interval > 0 && hours*3600/interval <= maxRawMetrics {
+		return "raw"
+	}
+
+	span := time.Duration(hours) * time.Hour
+	for _, resolution := range []string{models.ResolutionMinute, models.ResolutionHour} {
+		if span <= rs.retention[resolution] {
+			return resolution
+		}
+	}
+	return models.ResolutionDay
+}
+
+// Run aggregates buckets as they close until Stop is called. The first
+// pass also backfills rollups from the raw metrics still stored.
+func (rs *RollupService) Run() {
+	defer close(rs.done)
+
+	ticker := time.NewTicker(rollupInterval)
+	defer ticker.Stop()
+
+	for {
+		rs.rollup(time.Now())
+		select {
+		case <-ticker.C:
+		case <-rs.stop:
+			return
+		}
+	}
+}
+
+// Stop ends Run, waiting for the monitor being rolled up to finish. It must
+// only be called once Run has been started.
+func (rs *RollupService) Stop() {
+	close(rs.stop)
+	<-rs.done
+}
+
+// rollup aggregates every bucket of every monitor that closed since its last rollup
+func (rs *RollupService) rollup(now time.Time) {
+	monitors, err := rs.store.Monitors().List()
+	if err != nil {
+		log.Printf("Error loading monitors for rollup: %v", err)
+		return
+	}
+
+	for _, monitor := range monitors {
+		// A backfill can take a while, so a stop is not held up until it ends
+		select {
+		case <-rs.stop:
+			return
+		default:
+		}
+
+		for _, resolution := range models.Resolutions {
+			if err := rs.rollupMonitor(monitor.ID, resolution, now); err != nil {
+				log.Printf("Error rolling up %s metrics of %s: %v", resolution, monitor.Name, err)
+			}
+		}
+	}
+}
+
+// rollupMonitor aggregates a monitor's closed buckets of one resolution
+// that have not been rolled up yet
+func (rs *RollupService) rollupMonitor(monitorID primitive.ObjectID, resolution string, now time.Time) error {
+	size := models.ResolutionDuration(resolution)
+	retention := rs.retention[resolution]
+
+	end := now.Add(-rollupDelay).Truncate(size)
+	start := end.Add(-retention)
+	latest, err := rs.store.Rollups().Latest(monitorID, resolution)
+	switch {
+	case err == nil:
+		if next := latest.BucketStart.Add(size); next.After(start) {
+			start = next
+		}
+	case !errors.Is(err, database.ErrNotFound):
+		return err
+	}
+	if !start.Before(end) {
+		return nil
+	}
+
+	// Pages hold whole buckets, since both are aligned to the bucket size,
+	// and stop at end so open buckets are left alone
+	page := max(size, rollupPage)
+	for from := start; from.Before(end); from = from.Add(page) {
+		to := from.Add(page)
+		if to.After(end) {
+			to = end
+		}
+
+		metrics, err := rs.store.Metrics().Between(monitorID, from, to)
+		if err != nil {
+			return err
+		}
+		if len(metrics) == 0 {
+			continue
+		}
+
+		buckets := map[int64][]models.Metric{}
+		for _, metric := range metrics {
+			bucket := metric.CheckedAt.Truncate(size).UnixNano()
+			buckets[bucket] = append(buckets[bucket], metric)
+		}
+
+		rollups := make([]models.MetricRollup, 0, len(buckets))
+		for bucket, bucketMetrics := range buckets {
+			bucketStart := time.Unix(0, bucket).UTC()
+			rollup := models.NewMetricRollup(monitorID, resolution, bucketStart, bucketMetrics)
+			rollup.ExpiresAt = bucketStart.Add(retention)
+			rollups = append(rollups, rollup)
+		}
+		if err := rs.store.Rollups().Save(rollups); err != nil {
+			return err
+		}
+	}
+	return nil
+}
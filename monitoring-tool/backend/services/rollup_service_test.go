@@ -0,0 +1,161 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"monitoring-tool/config"
+	"monitoring-tool/database"
+	"monitoring-tool/models"
+)
+
+// pageRecorder records the ranges of raw metrics loaded through it
+type pageRecorder struct {
+	database.Store
+	pages [][2]time.Time
+}
+
+func (r *pageRecorder) Metrics() database.MetricStore {
+	return recordingMetricStore{r.Store.Metrics(), r}
+}
+
+type recordingMetricStore struct {
+	database.MetricStore
+	r *pageRecorder
+}
+
+func (m recordingMetricStore) Between(monitorID primitive.ObjectID, from, to time.Time) ([]models.Metric, error) {
+	m.r.pages = append(m.r.pages, [2]time.Time{from, to})
+	return m.MetricStore.Between(monitorID, from, to)
+}
+
+func TestResolutionFor(t *testing.T) {
+	rs := NewRollupService(database.NewMemoryStore(time.Hour), &config.Config{
+		RollupMinuteRetentionDays: 7,
+		RollupHourRetentionDays:   90,
+		RollupDayRetentionDays:    730,
+	})
+
+	tests := []struct {
+		name     string
+		hours    int
+		interval int
+		want     string
+	}{
+		{"hour checked every 30s", 1, 30, "raw"},
+		{"up to the raw cap", 8, 30, "raw"},
+		{"past the raw cap", 9, 30, models.ResolutionMinute},
+		{"day checked every 30s", 24, 30, models.ResolutionMinute},
+		{"day checked every 5 minutes", 24, 300, "raw"},
+		{"hour checked every second", 1, 1, models.ResolutionMinute},
+		{"week within minute retention", 7 * 24, 60, models.ResolutionMinute},
+		{"past minute retention", 7*24 + 1, 30, models.ResolutionHour},
+		{"within hour retention", 90 * 24, 30, models.ResolutionHour},
+		{"past hour retention", 90*24 + 1, 30, models.ResolutionDay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rs.ResolutionFor(tt.hours, tt.interval); got != tt.want {
+				t.Errorf("ResolutionFor(%d, %d) = %s, want %s", tt.hours, tt.interval, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollupMonitorPages(t *testing.T) {
+	memory := database.NewMemoryStore(72 * time.Hour)
+	store := &pageRecorder{Store: memory}
+	rs := NewRollupService(store, &config.Config{
+		RollupMinuteRetentionDays: 1,
+		RollupHourRetentionDays:   7,
+		RollupDayRetentionDays:    30,
+	})
+
+	// A check every 5 minutes for the 20 hours before 08:00 UTC
+	now := time.Now().UTC().Truncate(24 * time.Hour).Add(8 * time.Hour)
+	monitorID := primitive.NewObjectID()
+	for checkedAt := now.Add(-20 * time.Hour); checkedAt.Before(now); checkedAt = checkedAt.Add(5 * time.Minute) {
+		if err := memory.Metrics().Insert(&models.Metric{MonitorID: monitorID, Status: "up", ResponseTime: 10, CheckedAt: checkedAt}); err != nil {
+			t.Fatalf("failed to insert metric: %v", err)
+		}
+	}
+
+	tests := []struct {
+		resolution  string
+		page        time.Duration
+		wantRollups int
+		wantChecks  int // checks in closed buckets
+	}{
+		{models.ResolutionMinute, rollupPage, 240, 240},
+		{models.ResolutionHour, rollupPage, 19, 19 * 12},
+		{models.ResolutionDay, 24 * time.Hour, 1, 12 * 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.resolution, func(t *testing.T) {
+			store.pages = nil
+			if err := rs.rollupMonitor(monitorID, tt.resolution, now); err != nil {
+				t.Fatalf("rollupMonitor failed: %v", err)
+			}
+
+			// Contiguous pages, no longer than the page size, covering
+			// the retention up to the last closed bucket
+			size := models.ResolutionDuration(tt.resolution)
+			end := now.Add(-rollupDelay).Truncate(size)
+			if len(store.pages) == 0 {
+				t.Fatal("no metrics were loaded")
+			}
+			if first := store.pages[0][0]; !first.Equal(end.Add(-rs.retention[tt.resolution])) {
+				t.Errorf("first page starts at %s, want the start of the retention", first)
+			}
+			if last := store.pages[len(store.pages)-1][1]; !last.Equal(end) {
+				t.Errorf("last page ends at %s, want %s", last, end)
+			}
+			for i, page := range store.pages {
+				if span := page[1].Sub(page[0]); span <= 0 || span > tt.page {
+					t.Errorf("page %d spans %s, want at most %s", i, span, tt.page)
+				}
+				if i > 0 && !page[0].Equal(store.pages[i-1][1]) {
+					t.Errorf("page %d starts at %s, not where page %d ended", i, page[0], i-1)
+				}
+			}
+
+			rollups, err := memory.Rollups().Since(monitorID, tt.resolution, time.Time{})
+			if err != nil {
+				t.Fatalf("failed to list rollups: %v", err)
+			}
+			checks := 0
+			for _, rollup := range rollups {
+				checks += rollup.Count
+				if !rollup.BucketStart.Before(end) {
+					t.Errorf("rolled up open bucket %s", rollup.BucketStart)
+				}
+			}
+			if len(rollups) != tt.wantRollups || checks != tt.wantChecks {
+				t.Errorf("rollups = %d holding %d checks, want %d holding %d", len(rollups), checks, tt.wantRollups, tt.wantChecks)
+			}
+		})
+	}
+
+	// Later passes only load the buckets closed since
+	store.pages = nil
+	if err := rs.rollupMonitor(monitorID, models.ResolutionHour, now); err != nil {
+		t.Fatalf("second rollupMonitor failed: %v", err)
+	}
+	if len(store.pages) != 0 {
+		t.Errorf("second pass at the same time loaded %v, want nothing", store.pages)
+	}
+
+	if err := rs.rollupMonitor(monitorID, models.ResolutionHour, now.Add(time.Hour)); err != nil {
+		t.Fatalf("rollupMonitor an hour later failed: %v", err)
+	}
+	if len(store.pages) != 1 || !store.pages[0][0].Equal(now.Add(-time.Hour)) || !store.pages[0][1].Equal(now) {
+		t.Errorf("pass an hour later loaded %v, want only the hour before %s", store.pages, now)
+	}
+	if rollups, err := memory.Rollups().Since(monitorID, models.ResolutionHour, time.Time{}); err != nil || len(rollups) != 20 {
+		t.Errorf("hourly rollups after an hour = %d, %v, want 20", len(rollups), err)
+	}
+}
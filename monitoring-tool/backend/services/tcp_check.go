@@ -0,0 +1,29 @@
+// services/tcp_check.go
+package services
+
+import (
+	"net"
+	"time"
+
+	"monitoring-tool/models"
+)
+
+// checkTCP opens a TCP connection to the monitor's host:port and records
+// the connect latency as the response time
+func (ms *MonitorService) checkTCP(monitor models.Monitor) models.Metric {
+	address, err := monitor.TCPAddress()
+	if err != nil {
+		return models.Metric{Status: "down", Error: err.Error()}
+	}
+
+	startTime := time.Now()
+	conn, err := net.DialTimeout("tcp", address, time.Duration(monitor.Timeout)*time.Second)
+	responseTime := time.Since(startTime).Milliseconds()
+
+	if err != nil {
+		return models.Metric{Status: "down", ResponseTime: responseTime, Error: err.Error()}
+	}
+	conn.Close()
+
+	return models.Metric{Status: "up", ResponseTime: responseTime}
+}
@@ -0,0 +1,85 @@
+// services/tls_check.go
+package services
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"monitoring-tool/models"
+)
+
+// inspectCertificates describes the leaf certificate of a peer chain and
+// checks its chain and hostname validity independently of each other
+func inspectCertificates(certs []*x509.Certificate, hostname string) *models.TLSInfo {
+	if len(certs) == 0 {
+		return nil
+	}
+
+	leaf := certs[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, chainErr := leaf.Verify(x509.VerifyOptions{Intermediates: intermediates})
+
+	return &models.TLSInfo{
+		Subject:       leaf.Subject.String(),
+		Issuer:        leaf.Issuer.String(),
+		DNSNames:      leaf.DNSNames,
+		NotBefore:     leaf.NotBefore,
+		NotAfter:      leaf.NotAfter,
+		DaysRemaining: int(time.Until(leaf.NotAfter).Hours() / 24),
+		ChainValid:    chainErr == nil,
+		HostnameValid: leaf.VerifyHostname(hostname) == nil,
+	}
+}
+
+// tlsInfoFromState describes the certificate of a completed handshake. A
+// chain already verified by the client is trusted without re-verifying it.
+func tlsInfoFromState(state *tls.ConnectionState, hostname string) *models.TLSInfo {
+	info := inspectCertificates(state.PeerCertificates, hostname)
+	if info != nil && len(state.VerifiedChains) > 0 {
+		info.ChainValid = true
+	}
+	return info
+}
+
+// tlsInfoFromError recovers the certificate details from a failed handshake
+// so that a rejected certificate is still recorded on the metric
+func tlsInfoFromError(err error, hostname string) *models.TLSInfo {
+	var verifyErr *tls.CertificateVerificationError
+	if !errors.As(err, &verifyErr) {
+		return nil
+	}
+
+	return inspectCertificates(verifyErr.UnverifiedCertificates, hostname)
+}
+
+// applyTLSStatus marks an otherwise healthy metric as down when the
+// certificate is invalid, or degraded when it expires within the warning
+// window. A warning window of 0 disables the warning.
+func applyTLSStatus(metric *models.Metric, monitor models.Monitor) {
+	info := metric.TLS
+	if info == nil || metric.Status == "down" {
+		return
+	}
+
+	switch {
+	case time.Now().After(info.NotAfter):
+		metric.Status = "down"
+		metric.Error = fmt.Sprintf("certificate expired on %s", info.NotAfter.Format("2006-01-02"))
+	case !info.HostnameValid:
+		metric.Status = "down"
+		metric.Error = "certificate is not valid for this hostname"
+	case !info.ChainValid:
+		metric.Status = "down"
+		metric.Error = "certificate chain is not trusted"
+	case info.DaysRemaining < monitor.TLSExpiryWarningDays:
+		metric.Status = "degraded"
+		metric.Error = fmt.Sprintf("certificate expires in %d days (%s)", info.DaysRemaining, info.NotAfter.Format("2006-01-02"))
+	}
+}